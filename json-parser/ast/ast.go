@@ -0,0 +1,184 @@
+// Package ast parses a document into a syntax tree that remembers where
+// every node is in the source and keeps the whitespace and comments
+// between them, so that edits can change a single value and leave the rest
+// of the file byte for byte as it was.
+package ast
+
+import (
+	"bytes"
+	"io"
+	"json-parser/parser"
+	"strings"
+)
+
+// Node is a value in the document, or the key of an object member.
+type Node struct {
+	Kind parser.Kind
+	// Value holds the parsed value of a scalar or key. It is nil for
+	// objects and arrays.
+	Value parser.Value
+	// Key is the key of the member this node is the value of.
+	Key *Node
+	// Children are the values of an object's members or an array's
+	// elements.
+	Children []*Node
+	// Start is the first byte of the node and End is just past it; for
+	// objects and arrays they include the brackets.
+	Start, End parser.Position
+	// Leading is the source text between the previous token and the node:
+	// whitespace, comments and the ',' or ':' in between.
+	Leading string
+	// Closing is the source text between the last child of an object or
+	// array and its closing bracket.
+	Closing string
+}
+
+// File is a parsed document together with its source.
+type File struct {
+	Source []byte
+	Root   *Node
+	// Trailing is the source text after the root value.
+	Trailing string
+}
+
+// Parse builds the syntax tree of data. Recovery mode in opts is ignored,
+// as a tree can only be built for a valid document.
+func Parse(data []byte, opts parser.Options) (*File, error) {
+	opts.Recover = false
+	d := parser.NewDecoderWithOptions(bytes.NewReader(data), opts)
+	f := &File{Source: data}
+	var open []*Node
+	var key *Node
+	var prev int64
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			f.Trailing = string(data[prev:])
+			return f, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		leading := string(data[prev:tok.Pos.Offset])
+		prev = tok.End.Offset
+
+		var n *Node
+		switch tok.Kind {
+		case parser.ObjectKey:
+			key = &Node{Kind: parser.StringKind, Value: tok.Value, Start: tok.Pos, End: tok.End, Leading: leading}
+			continue
+		case parser.ObjectEnd, parser.ArrayEnd:
+			n = open[len(open)-1]
+			open = open[:len(open)-1]
+			n.Closing = leading
+			n.End = tok.End
+			continue
+		case parser.ObjectStart:
+			n = &Node{Kind: parser.ObjectKind}
+		case parser.ArrayStart:
+			n = &Node{Kind: parser.ArrayKind}
+		case parser.ScalarValue:
+			n = &Node{Kind: tok.Value.Kind(), Value: tok.Value, End: tok.End}
+		}
+		n.Start = tok.Pos
+		n.Leading = leading
+		if len(open) == 0 {
+			f.Root = n
+		} else {
+			parent := open[len(open)-1]
+			if parent.Kind == parser.ObjectKind {
+				n.Key, key = key, nil
+			}
+			parent.Children = append(parent.Children, n)
+		}
+		if tok.Kind == parser.ObjectStart || tok.Kind == parser.ArrayStart {
+			open = append(open, n)
+		}
+	}
+}
+
+// Text returns the source text of n.
+func (f *File) Text(n *Node) string {
+	return string(f.Source[n.Start.Offset:n.End.Offset])
+}
+
+// Bytes writes the tree back out from its parts. For a File returned by
+// Parse the result is identical to Source.
+func (f *File) Bytes() []byte {
+	var buf bytes.Buffer
+	if f.Root != nil {
+		f.write(&buf, f.Root)
+	}
+	buf.WriteString(f.Trailing)
+	return buf.Bytes()
+}
+
+func (f *File) write(buf *bytes.Buffer, n *Node) {
+	if n.Key != nil {
+		buf.WriteString(n.Key.Leading)
+		buf.WriteString(f.Text(n.Key))
+	}
+	buf.WriteString(n.Leading)
+	if n.Kind != parser.ObjectKind && n.Kind != parser.ArrayKind {
+		buf.WriteString(f.Text(n))
+		return
+	}
+	// The brackets are the first and last byte of a container.
+	buf.WriteByte(f.Source[n.Start.Offset])
+	for _, c := range n.Children {
+		f.write(buf, c)
+	}
+	buf.WriteString(n.Closing)
+	buf.WriteByte(f.Source[n.End.Offset-1])
+}
+
+// Replace returns the source with the text of n swapped for text. Nothing
+// else changes, so formatting and comments elsewhere are kept. The tree
+// still describes the old source; parse the result to edit further.
+func (f *File) Replace(n *Node, text string) []byte {
+	out := make([]byte, 0, len(f.Source)+len(text))
+	out = append(out, f.Source[:n.Start.Offset]...)
+	out = append(out, text...)
+	return append(out, f.Source[n.End.Offset:]...)
+}
+
+// ReplaceValue is like Replace with v written as minified JSON.
+func (f *File) ReplaceValue(n *Node, v parser.Value) []byte {
+	return f.Replace(n, string(parser.Marshal(v)))
+}
+
+// Member returns the value of the member named key of an object node, or
+// nil if there is none. With duplicate keys the last one wins.
+func (n *Node) Member(key string) *Node {
+	var found *Node
+	for _, c := range n.Children {
+		if c.Key != nil && c.Key.Value.(parser.String).Value == key {
+			found = c
+		}
+	}
+	return found
+}
+
+// Comments returns the // and /* */ comments in the source text s, such as
+// a node's Leading text, without their delimiters.
+func Comments(s string) []string {
+	var comments []string
+	for {
+		i := strings.Index(s, "/")
+		if i < 0 || i+1 >= len(s) {
+			return comments
+		}
+		switch s[i+1] {
+		case '/':
+			text, rest, _ := strings.Cut(s[i+2:], "\n")
+			comments = append(comments, strings.TrimSuffix(text, "\r"))
+			s = rest
+		case '*':
+			text, rest, _ := strings.Cut(s[i+2:], "*/")
+			comments = append(comments, text)
+			s = rest
+		default:
+			s = s[i+1:]
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package ast
+
+import (
+	"json-parser/parser"
+	"slices"
+	"testing"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		dialect parser.Dialect
+	}{
+		{"scalar", "42", parser.DialectJSON},
+		{"padded", "  \n\"x\"\n\n", parser.DialectJSON},
+		{"empty containers", "[{ }, [\n]]", parser.DialectJSON},
+		{"nested", "{\n  \"a\" : [1, 2,3],\n\t\"b\":{\"c\":null}\n}\n", parser.DialectJSON},
+		{"comments", "// head\n{\n  /* a */ \"a\": 1, // one\n  \"b\": [true /* t */]\n}\n// tail\n", parser.DialectJSONC},
+		{"trailing comma", "[1, 2, ]", parser.DialectJSONC},
+		{"json5", "{a: 'x', \"b\": +1, c: 0x1F,}", parser.DialectJSON5},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := Parse([]byte(tc.input), parser.Options{RFC: parser.RFC8259, Dialect: tc.dialect})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual := string(f.Bytes()); actual != tc.input {
+				t.Fatalf("unexpected output.\nexpected: %q\nactual: %q", tc.input, actual)
+			}
+		})
+	}
+}
+
+func TestParsePositions(t *testing.T) {
+	input := "{\n  \"a\": [1, \"two\"],\n  \"b\": {}\n}"
+	f, err := Parse([]byte(input), parser.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a := f.Root.Member("a")
+	if a == nil || len(a.Children) != 2 {
+		t.Fatalf("unexpected member a: %+v", a)
+	}
+	tests := []struct {
+		name       string
+		node       *Node
+		text       string
+		start, end parser.Position
+	}{
+		{"root", f.Root, input, parser.Position{Offset: 0, Line: 1, Column: 1}, parser.Position{Offset: 32, Line: 4, Column: 2}},
+		{"key", a.Key, `"a"`, parser.Position{Offset: 4, Line: 2, Column: 3}, parser.Position{Offset: 7, Line: 2, Column: 6}},
+		{"array", a, `[1, "two"]`, parser.Position{Offset: 9, Line: 2, Column: 8}, parser.Position{Offset: 19, Line: 2, Column: 18}},
+		{"string", a.Children[1], `"two"`, parser.Position{Offset: 13, Line: 2, Column: 12}, parser.Position{Offset: 18, Line: 2, Column: 17}},
+		{"object", f.Root.Member("b"), `{}`, parser.Position{Offset: 28, Line: 3, Column: 8}, parser.Position{Offset: 30, Line: 3, Column: 10}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := f.Text(tc.node); actual != tc.text {
+				t.Fatalf("unexpected text.\nexpected: %q\nactual: %q", tc.text, actual)
+			}
+			if tc.node.Start != tc.start || tc.node.End != tc.end {
+				t.Fatalf("unexpected span.\nexpected: %#v-%#v\nactual: %#v-%#v", tc.start, tc.end, tc.node.Start, tc.node.End)
+			}
+		})
+	}
+}
+
+func TestReplace(t *testing.T) {
+	input := "{\n  // port to listen on\n  \"port\": 8080,\n  \"hosts\": [\"a\",   \"b\"]\n}\n"
+	f, err := Parse([]byte(input), parser.Options{Dialect: parser.DialectJSONC})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actual := string(f.ReplaceValue(f.Root.Member("port"), parser.Number{Literal: "9090"}))
+	want := "{\n  // port to listen on\n  \"port\": 9090,\n  \"hosts\": [\"a\",   \"b\"]\n}\n"
+	if actual != want {
+		t.Fatalf("unexpected output.\nexpected: %q\nactual: %q", want, actual)
+	}
+	actual = string(f.Replace(f.Root.Member("hosts").Children[1], `"c"`))
+	want = "{\n  // port to listen on\n  \"port\": 8080,\n  \"hosts\": [\"a\",   \"c\"]\n}\n"
+	if actual != want {
+		t.Fatalf("unexpected output.\nexpected: %q\nactual: %q", want, actual)
+	}
+}
+
+func TestComments(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"\n  ", nil},
+		{", // one\n  ", []string{" one"}},
+		{"/* a */ /*b*/:", []string{" a ", "b"}},
+		{"// crlf\r\n// last", []string{" crlf", " last"}},
+	}
+
+	for _, tc := range tests {
+		if actual := Comments(tc.input); !slices.Equal(actual, tc.want) {
+			t.Fatalf("unexpected comments in %q.\nexpected: %q\nactual: %q", tc.input, tc.want, actual)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	if _, err := Parse([]byte(`{"a": }`), parser.Options{Recover: true}); err == nil {
+		t.Fatalf("expected a syntax error")
+	}
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"json-parser/parser"
+	"json-parser/schema"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileResult is the outcome of checking one file in a batch.
+type fileResult struct {
+	file     string
+	err      error
+	duration time.Duration
+}
+
+// schemaError reports the violations found in a file that was valid JSON.
+type schemaError struct {
+	violations []schema.Violation
+}
+
+func (e *schemaError) Error() string {
+	msgs := make([]string, len(e.violations))
+	for i, v := range e.violations {
+		msgs[i] = v.String()
+	}
+	return fmt.Sprintf("%d schema violations: %s", len(e.violations), strings.Join(msgs, "; "))
+}
+
+// expandPaths turns the command line arguments into a list of files.
+// Directories are searched recursively for files with one of the given
+// extensions and arguments containing glob characters are expanded.
+func expandPaths(args []string, extensions []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	addFile := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			files = append(files, name)
+		}
+	}
+	for _, arg := range args {
+		matches := []string{arg}
+		if strings.ContainsAny(arg, "*?[") {
+			var err error
+			if matches, err = filepath.Glob(arg); err != nil {
+				return nil, fmt.Errorf("%s: %w", arg, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("%s: no files match", arg)
+			}
+		}
+		for _, m := range matches {
+			info, err := os.Stat(m)
+			if err != nil || !info.IsDir() {
+				// Missing files are reported when they are checked.
+				addFile(m)
+				continue
+			}
+			err = filepath.WalkDir(m, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !d.IsDir() && slices.Contains(extensions, filepath.Ext(path)) {
+					addFile(path)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return files, nil
+}
+
+// isBatch reports whether the arguments name more than a single file.
+func isBatch(args []string) bool {
+	if len(args) != 1 || strings.ContainsAny(args[0], "*?[") {
+		return true
+	}
+	info, err := os.Stat(args[0])
+	return err == nil && info.IsDir()
+}
+
+// checkFiles runs check on every file using the given number of workers
+// and returns the results in the order of files.
+func checkFiles(files []string, workers int, check func(fileName string) error) []fileResult {
+	results := make([]fileResult, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				start := time.Now()
+				err := check(files[i])
+				results[i] = fileResult{file: files[i], err: err, duration: time.Since(start)}
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// fileChecker returns the function validating a single file of a batch.
+func fileChecker(opts parser.Options, ndjson bool, schemaFile string) (func(fileName string) error, error) {
+	if ndjson {
+		return func(fileName string) error {
+			summary, err := validateNDJSONFromFile(fileName, opts)
+			if err == nil && summary.failed > 0 {
+				err = fmt.Errorf("%d of %d records invalid", summary.failed, summary.passed+summary.failed)
+			}
+			return err
+		}, nil
+	}
+	if schemaFile == "" {
+		return func(fileName string) error {
+			return validateJSONFromFile(fileName, opts)
+		}, nil
+	}
+	schemaDoc, err := parseFile(schemaFile, parser.Options{})
+	if err != nil {
+		return nil, err
+	}
+	s, err := schema.Compile(schemaDoc)
+	if err != nil {
+		return nil, err
+	}
+	return func(fileName string) error {
+		doc, err := parseFile(fileName, opts)
+		if err != nil {
+			return err
+		}
+		if violations := s.Validate(doc); len(violations) > 0 {
+			return &schemaError{violations: violations}
+		}
+		return nil
+	}, nil
+}
+
+// validateFiles checks every file named by args and prints the results as
+// text or as the given report format. It returns the exit code.
+func validateFiles(args []string, opts parser.Options, ndjson bool, schemaFile string, workers int, report string) int {
+	extensions := []string{".json"}
+	if ndjson {
+		extensions = []string{".ndjson", ".jsonl"}
+	}
+	files, err := expandPaths(args, extensions)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	check, err := fileChecker(opts, ndjson, schemaFile)
+	if err != nil {
+		printError(schemaFile, err)
+		return 2
+	}
+	results := checkFiles(files, workers, check)
+	switch report {
+	case "":
+		printResults(results)
+	case "json":
+		err = writeJSONReport(os.Stdout, results)
+	case "junit":
+		err = writeJUnitReport(os.Stdout, results)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	status := 0
+	for _, r := range results {
+		status = max(status, exitCode(r.err))
+	}
+	return status
+}
+
+// printResults reports the failed files on stderr and a summary on stdout.
+func printResults(results []fileResult) {
+	failed := 0
+	for _, r := range results {
+		if r.err == nil {
+			continue
+		}
+		failed++
+		var schemaErr *schemaError
+		if errors.As(r.err, &schemaErr) {
+			printViolations(r.file, schemaErr.violations)
+			continue
+		}
+		printError(r.file, r.err)
+	}
+	printStatus(fmt.Sprintf("%d files valid, %d invalid", len(results)-failed, failed))
+}
+
+// writeJSONReport writes the results as a JSON document built with the
+// parser's own value types.
+func writeJSONReport(w io.Writer, results []fileResult) error {
+	member := func(key string, v parser.Value) parser.Member {
+		return parser.Member{Key: parser.NewString(key), Value: v}
+	}
+	count := func(n int) parser.Number {
+		return parser.Number{Literal: strconv.Itoa(n)}
+	}
+	list := &parser.Array{}
+	failed := 0
+	for _, r := range results {
+		entry := &parser.Object{Members: []parser.Member{
+			member("file", parser.NewString(r.file)),
+			member("valid", parser.Bool(r.err == nil)),
+		}}
+		if r.err != nil {
+			failed++
+			msg := r.err.Error()
+			if pos, m, ok := diagnosticPosition(r.err); ok {
+				msg = m
+				entry.Members = append(entry.Members, member("line", count(pos.Line)), member("column", count(pos.Column)))
+			}
+			entry.Members = append(entry.Members, member("error", parser.NewString(msg)))
+		}
+		list.Elements = append(list.Elements, entry)
+	}
+	report := &parser.Object{Members: []parser.Member{
+		member("files", count(len(results))),
+		member("valid", count(len(results)-failed)),
+		member("invalid", count(failed)),
+		member("results", list),
+	}}
+	if err := parser.WriteValue(w, report, "  "); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type junitSuite struct {
+	XMLName  xml.Name    `xml:"testsuite"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name    string        `xml:"name,attr"`
+	Time    string        `xml:"time,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnitReport writes the results as a JUnit XML test suite with one
+// test case per file.
+func writeJUnitReport(w io.Writer, results []fileResult) error {
+	seconds := func(d time.Duration) string {
+		return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+	}
+	suite := junitSuite{Name: "json-parser", Tests: len(results)}
+	var total time.Duration
+	for _, r := range results {
+		c := junitCase{Name: r.file, Time: seconds(r.duration)}
+		if r.err != nil {
+			suite.Failures++
+			msg := r.err.Error()
+			if pos, m, ok := diagnosticPosition(r.err); ok {
+				msg = fmt.Sprintf("%s:%d:%d: %s", r.file, pos.Line, pos.Column, m)
+			}
+			c.Failure = &junitFailure{Message: msg, Text: msg}
+		}
+		total += r.duration
+		suite.Cases = append(suite.Cases, c)
+	}
+	suite.Time = seconds(total)
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suite); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
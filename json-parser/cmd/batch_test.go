@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"json-parser/parser"
+	"slices"
+	"testing"
+)
+
+func TestExpandPaths(t *testing.T) {
+	files, err := expandPaths([]string{"../test_files/step3", "../test_files/step1/*.json", "../test_files/step3/valid.json"}, []string{".json"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"../test_files/step3/invalid.json",
+		"../test_files/step3/valid.json",
+		"../test_files/step1/invalid.json",
+		"../test_files/step1/invalid2.json",
+		"../test_files/step1/valid.json",
+	}
+	if !slices.Equal(files, want) {
+		t.Fatalf("unexpected files.\nexpected: %q\nactual: %q", want, files)
+	}
+	if _, err := expandPaths([]string{"../test_files/*.nothing"}, []string{".json"}); err == nil {
+		t.Fatalf("expected an error for a glob without matches")
+	}
+}
+
+func TestCheckFiles(t *testing.T) {
+	files, err := expandPaths([]string{"../test_files/step5"}, []string{".json"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	check, err := fileChecker(parser.Options{}, false, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	results := checkFiles(files, 4, check)
+	failed := 0
+	for i, r := range results {
+		if r.file != files[i] {
+			t.Fatalf("result %d is for %s, expected %s", i, r.file, files[i])
+		}
+		if r.err != nil {
+			failed++
+		}
+	}
+	if failed != len(files)-3 {
+		t.Fatalf("expected all but the 3 valid files to fail, %d of %d failed", failed, len(files))
+	}
+
+	var buf bytes.Buffer
+	if err := writeJSONReport(&buf, results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	report, err := parser.Parse(&buf)
+	if err != nil {
+		t.Fatalf("JSON report is not valid JSON: %v", err)
+	}
+	if v, _ := report.(*parser.Object).Get("invalid"); v.(parser.Number).Literal != "32" {
+		t.Fatalf("unexpected invalid count %s", parser.Marshal(v))
+	}
+
+	buf.Reset()
+	if err := writeJUnitReport(&buf, results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var suite junitSuite
+	if err := xml.Unmarshal(buf.Bytes(), &suite); err != nil {
+		t.Fatalf("JUnit report is not valid XML: %v", err)
+	}
+	if suite.Tests != len(files) || suite.Failures != failed || len(suite.Cases) != len(files) {
+		t.Fatalf("unexpected suite counts: %d tests, %d failures", suite.Tests, suite.Failures)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"json-parser/parser"
+	"os"
+)
+
+// runCanon prints the RFC 8785 canonical form of a document. No newline is
+// added, so the output can be hashed or signed as it is.
+func runCanon(args []string) int {
+	fs := flag.NewFlagSet("canon", flag.ExitOnError)
+	pf := addParserFlags(fs)
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Println("Usage: json-parser canon <file>")
+		return 2
+	}
+	opts, err := pf.options()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	fileName := fs.Arg(0)
+	doc, err := parseFile(fileName, opts)
+	if err != nil {
+		printError(fileName, err)
+		return exitCode(err)
+	}
+	out, err := parser.Canonicalize(doc)
+	if err != nil {
+		printError(fileName, err)
+		return 1
+	}
+	if _, err := os.Stdout.Write(out); err != nil {
+		printError(fileName, err)
+		return exitCode(err)
+	}
+	return 0
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"json-parser/convert"
+	"json-parser/parser"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func runConvert(args []string) int {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	from := fs.String("from", "", "input format: json or csv (default from the file extension)")
+	to := fs.String("to", "", "output format: yaml, toml, csv or json (default json for CSV input)")
+	pf := addParserFlags(fs)
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Println("Usage: json-parser convert [-from json|csv] -to yaml|toml|csv|json <file>")
+		return 2
+	}
+	opts, err := pf.options()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	fileName := fs.Arg(0)
+	if *from == "" {
+		*from = "json"
+		if strings.EqualFold(filepath.Ext(fileName), ".csv") {
+			*from = "csv"
+		}
+	}
+	if *to == "" && *from == "csv" {
+		*to = "json"
+	}
+
+	var doc parser.Value
+	switch *from {
+	case "json":
+		doc, err = parseFile(fileName, opts)
+	case "csv":
+		doc, err = readCSVFile(fileName)
+	default:
+		fmt.Fprintf(os.Stderr, "unsupported input format %q, expected json or csv\n", *from)
+		return 2
+	}
+	if err != nil {
+		printError(fileName, err)
+		return exitCode(err)
+	}
+
+	var buf bytes.Buffer
+	switch *to {
+	case "yaml":
+		err = convert.WriteYAML(&buf, doc)
+	case "toml":
+		err = convert.WriteTOML(&buf, doc)
+	case "csv":
+		err = convert.WriteCSV(&buf, doc)
+	case "json":
+		if err = parser.WriteValue(&buf, doc, "  "); err == nil {
+			buf.WriteByte('\n')
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unsupported output format %q, expected yaml, toml, csv or json\n", *to)
+		return 2
+	}
+	if err != nil {
+		printError(fileName, err)
+		return 1
+	}
+	if _, err := os.Stdout.Write(buf.Bytes()); err != nil {
+		printError(fileName, err)
+		return exitCode(err)
+	}
+	return 0
+}
+
+func readCSVFile(fileName string) (parser.Value, error) {
+	f, err := openInput(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return convert.ReadCSV(bufio.NewReader(f))
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"json-parser/diff"
+	"json-parser/parser"
+	"os"
+)
+
+// runDiff prints the structural differences between two documents. Like
+// diff(1) it exits with 0 when they are equal and 1 when they differ.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	asPatch := fs.Bool("patch", false, "print an RFC 6902 JSON Patch instead of a list of changes")
+	pf := addParserFlags(fs)
+	_ = fs.Parse(args)
+	if fs.NArg() != 2 {
+		fmt.Println("Usage: json-parser diff [-patch] <a.json> <b.json>")
+		return 2
+	}
+	opts, err := pf.options()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	var docs [2]parser.Value
+	for i, fileName := range fs.Args() {
+		if docs[i], err = parseFile(fileName, opts); err != nil {
+			printError(fileName, err)
+			return 2
+		}
+	}
+
+	changes := diff.Compare(docs[0], docs[1])
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	if *asPatch {
+		out.Write(parser.Marshal(diff.Patch(changes)))
+		out.WriteByte('\n')
+	} else {
+		for _, c := range changes {
+			switch c.Op {
+			case diff.Added:
+				fmt.Fprintf(out, "+ %s: %s\n", c.Path, parser.Marshal(c.New))
+			case diff.Removed:
+				fmt.Fprintf(out, "- %s: %s\n", c.Path, parser.Marshal(c.Old))
+			case diff.Changed:
+				fmt.Fprintf(out, "~ %s: %s -> %s\n", c.Path, parser.Marshal(c.Old), parser.Marshal(c.New))
+			}
+		}
+	}
+	if len(changes) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"json-parser/parser"
+	"os"
+	"strings"
+)
+
+func runFmt(args []string) int {
+	fs := flag.NewFlagSet("fmt", flag.ExitOnError)
+	indent := fs.Int("indent", 2, "number of spaces per level of nesting")
+	tab := fs.Bool("tab", false, "indent with tabs instead of spaces")
+	write := fs.Bool("w", false, "write the result back to the file instead of stdout")
+	pf := addParserFlags(fs)
+	_ = fs.Parse(args)
+	if *indent < 0 {
+		fmt.Fprintln(os.Stderr, "indent must not be negative")
+		return 2
+	}
+	indentStr := strings.Repeat(" ", *indent)
+	if *tab {
+		indentStr = "\t"
+	}
+	if indentStr == "" {
+		fmt.Fprintln(os.Stderr, "indent must be at least 1, use min to remove whitespace")
+		return 2
+	}
+	return formatFiles(fs.Args(), pf, indentStr, *write)
+}
+
+func runMin(args []string) int {
+	fs := flag.NewFlagSet("min", flag.ExitOnError)
+	write := fs.Bool("w", false, "write the result back to the file instead of stdout")
+	pf := addParserFlags(fs)
+	_ = fs.Parse(args)
+	return formatFiles(fs.Args(), pf, "", *write)
+}
+
+func formatFiles(fileNames []string, pf *parserFlags, indent string, write bool) int {
+	if len(fileNames) < 1 {
+		fmt.Println("No file provided")
+		return 2
+	}
+	opts, err := pf.options()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if write && opts.Dialect != parser.DialectJSON {
+		fmt.Fprintln(os.Stderr, "-w would drop the comments of jsonc and json5 input, write to stdout instead")
+		return 2
+	}
+	status := 0
+	for _, fileName := range fileNames {
+		out, err := formatFile(fileName, opts, indent)
+		if err == nil {
+			err = writeOutput(fileName, out, write)
+		}
+		if err != nil {
+			printError(fileName, err)
+			status = max(status, exitCode(err))
+		}
+	}
+	return status
+}
+
+// formatFile re-emits the document in fileName with the given indent. The
+// whole result is kept in memory so that nothing is written for a file
+// that turns out to be invalid.
+func formatFile(fileName string, opts parser.Options, indent string) ([]byte, error) {
+	f, err := openInput(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var buf bytes.Buffer
+	if err := parser.Format(&buf, parser.NewDecoderWithOptions(bufio.NewReader(f), withWarnings(opts, fileName)), indent); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
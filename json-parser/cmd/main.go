@@ -2,372 +2,307 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"io/fs"
+	"json-parser/parser"
 	"os"
-	"slices"
-	"unicode"
+	"runtime"
 )
 
+// commands maps a subcommand name to the function running it. Each
+// function receives the arguments after the name and returns the exit code.
+var commands = map[string]func(args []string) int{
+	"canon":   runCanon,
+	"convert": runConvert,
+	"diff":    runDiff,
+	"fmt":     runFmt,
+	"min":     runMin,
+	"patch":   runPatch,
+	"query":   runQuery,
+	"stats":   runStats,
+}
+
+// quiet suppresses all output about the documents being validated, leaving
+// only the exit code: 0 for valid, 1 for invalid and 2 for usage errors or
+// input that could not be read.
+var quiet bool
+
 func main() {
+	if len(os.Args) > 1 {
+		if run, ok := commands[os.Args[1]]; ok {
+			os.Exit(run(os.Args[2:]))
+		}
+	}
+
+	ndjson := flag.Bool("ndjson", false, "validate newline-delimited JSON, one document per line")
+	schemaFile := flag.String("schema", "", "also check the document against this JSON Schema file")
+	workers := flag.Int("j", runtime.NumCPU(), "number of files to validate concurrently")
+	report := flag.String("report", "", "print a json or junit report instead of the summary when validating several files")
+	flag.BoolVar(&quiet, "q", false, "print nothing, only report the result through the exit code")
+	pf := addParserFlags(flag.CommandLine)
 	flag.Parse()
 	args := flag.Args()
 	if len(args) < 1 {
-		fmt.Println("No file provided")
-		return
+		if !stdinPiped() {
+			fmt.Println("No file provided")
+			os.Exit(2)
+		}
+		args = []string{"-"}
+	}
+	opts, err := pf.options()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if *report != "" && *report != "json" && *report != "junit" {
+		fmt.Printf("unsupported report format %q, expected json or junit\n", *report)
+		os.Exit(2)
+	}
+	if *report != "" || isBatch(args) {
+		os.Exit(validateFiles(args, opts, *ndjson, *schemaFile, *workers, *report))
 	}
 
 	fileName := args[0]
-	validJson := validateJSONFromFile(fileName)
-	if validJson {
-		fmt.Println("Valid JSON")
-	} else {
-		fmt.Println("Invalid JSON")
+	if *ndjson {
+		summary, err := validateNDJSONFromFile(fileName, opts)
+		if err != nil {
+			printError(fileName, err)
+			os.Exit(exitCode(err))
+		}
+		printStatus(fmt.Sprintf("%d records valid, %d invalid", summary.passed, summary.failed))
+		if summary.failed > 0 {
+			os.Exit(1)
+		}
+		return
 	}
+	if *schemaFile != "" {
+		os.Exit(validateWithSchema(fileName, *schemaFile, opts))
+	}
+	if err := validateJSONFromFile(fileName, opts); err != nil {
+		printError(fileName, err)
+		if errors.Is(err, parser.ErrInvalid) {
+			printStatus("Invalid JSON")
+		}
+		os.Exit(exitCode(err))
+	}
+	printStatus("Valid JSON")
 }
 
-func validateJSONFromFile(fileName string) bool {
-	f, err := os.Open(fileName)
-	if err != nil {
-		fmt.Printf("Error opening file: %s\n", err)
-		return false
+// exitCode returns the exit status for the outcome of validating a
+// document: 1 when it is not valid and 2 when it could not be read.
+func exitCode(err error) int {
+	var pathErr *fs.PathError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &pathErr):
+		return 2
 	}
-	defer f.Close()
-	reader := bufio.NewReader(f)
-	return validateJSON(reader)
+	return 1
 }
 
-func validateJSON(reader *bufio.Reader) bool {
-	// Only allow a single top-level value that is either an object or an array.
-	iterateOverContinuousWhitespace(reader)
-	// Peek first non-whitespace rune to decide top-level type
-	r, _, err := reader.ReadRune()
-	if err != nil {
-		return false
+// printStatus prints the verdict on the input to stdout unless -q is set.
+func printStatus(msg string) {
+	if !quiet {
+		fmt.Println(msg)
+	}
+}
+
+// stdinPiped reports whether standard input is redirected from a pipe or
+// file rather than attached to a terminal.
+func stdinPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// parserFlags holds the flags shared by every command that reads JSON.
+type parserFlags struct {
+	rfc       *string
+	dialect   *string
+	dupKeys   *string
+	strictUTF *bool
+	numbers   *string
+	unsafeInt *bool
+	recover   *bool
+	maxErrors *int
+	maxDepth  *int
+	maxString *int
+	maxNumber *int
+	maxSize   *int64
+}
+
+func addParserFlags(fs *flag.FlagSet) *parserFlags {
+	return &parserFlags{
+		rfc:       fs.String("rfc", "4627", "specification to follow: 4627 (object or array at top level) or 8259 (any value)"),
+		dialect:   fs.String("dialect", "json", "syntax to accept: json, jsonc (comments and trailing commas) or json5"),
+		dupKeys:   fs.String("dupkeys", "allow", "what to do with duplicate keys in an object: allow, warn or error"),
+		strictUTF: fs.Bool("strict-unicode", false, "reject malformed UTF-8 and unpaired surrogates in \\u escapes"),
+		numbers:   fs.String("numbers", "exact", "representation numbers must fit: exact, float64 or int64"),
+		unsafeInt: fs.Bool("warn-unsafe-int", false, "warn about integers beyond 2^53 that lose precision in JavaScript"),
+		recover:   fs.Bool("recover", false, "keep going after syntax errors and report all of them"),
+		maxErrors: fs.Int("max-errors", 100, "with -recover, stop after this many errors, 0 for no limit"),
+		maxDepth:  fs.Int("max-depth", 0, "maximum nesting depth of objects and arrays, 0 for no limit"),
+		maxString: fs.Int("max-string", 0, "maximum length of a string in bytes, 0 for no limit"),
+		maxNumber: fs.Int("max-number", 0, "maximum length of a number in bytes, 0 for no limit"),
+		maxSize:   fs.Int64("max-size", 0, "maximum size of a document in bytes, or of each record with -ndjson, 0 for no limit"),
 	}
-	if r != '{' && r != '[' {
-		// Top-level must be object or array
-		return false
+}
+
+func (pf *parserFlags) options() (parser.Options, error) {
+	var opts parser.Options
+	var err error
+	if opts.RFC, err = parser.ParseRFC(*pf.rfc); err != nil {
+		return opts, err
 	}
-	_ = reader.UnreadRune()
-	var ok bool
-	if r == '{' {
-		ok = validateJSONObject(reader)
-	} else {
-		ok = validateJSONArray(reader)
+	if opts.Dialect, err = parser.ParseDialect(*pf.dialect); err != nil {
+		return opts, err
 	}
-	if !ok {
-		return false
+	if opts.DuplicateKeys, err = parser.ParseDuplicateKeyPolicy(*pf.dupKeys); err != nil {
+		return opts, err
 	}
-	iterateOverContinuousWhitespace(reader)
-	_, _, err = reader.ReadRune()
-	if err == io.EOF {
-		return true
+	if opts.Numbers, err = parser.ParseNumberMode(*pf.numbers); err != nil {
+		return opts, err
 	}
-	// Any non-whitespace after a complete value is invalid JSON
-	return false
+	if *pf.maxErrors < 0 || *pf.maxDepth < 0 || *pf.maxString < 0 || *pf.maxNumber < 0 || *pf.maxSize < 0 {
+		return opts, errors.New("limits must not be negative")
+	}
+	opts.StrictUnicode = *pf.strictUTF
+	opts.WarnUnsafeIntegers = *pf.unsafeInt
+	opts.Recover = *pf.recover
+	opts.MaxErrors = *pf.maxErrors
+	opts.MaxDepth = *pf.maxDepth
+	opts.MaxStringLength = *pf.maxString
+	opts.MaxNumberLength = *pf.maxNumber
+	opts.MaxDocumentSize = *pf.maxSize
+	return opts, nil
 }
 
-func validateJSONObject(reader *bufio.Reader) bool {
-	/*
-		Rules for a json object:
-		1. JSON object must start with { and end with }
-		2. JSON object must have a key value pair, and a comma after each key value pair
-		3. Key must be enclosed in double quotes
-	*/
-	nextExpectedDelims := []rune{'{'}
-	for {
-		iterateOverContinuousWhitespace(reader)
-		r, _, err := reader.ReadRune()
-		if err != nil {
-			if err != io.EOF {
-				fmt.Printf("Error reading file: %s\n", err)
-			}
-			return false
-		}
-		// After removing all the whitespace, first should be the end bracket or key
-		if !slices.Contains(nextExpectedDelims, r) {
-			return false
-		}
-		if r == '{' {
-			nextExpectedDelims = []rune{'}', '"'}
-		} else if r == '}' {
-			return true
-		} else if r == '"' {
-			if !validateString(reader) {
-				return false
-			}
-			nextExpectedDelims = []rune{':'}
-		} else if r == ':' {
-			if !validateJSONValue(reader) {
-				return false
-			}
-			nextExpectedDelims = []rune{',', '}'}
-		} else if r == ',' {
-			nextExpectedDelims = []rune{'"'}
-		} else {
-			return false
-		}
+// withWarnings returns opts with warnings printed against fileName.
+func withWarnings(opts parser.Options, fileName string) parser.Options {
+	opts.Warn = func(err error) {
+		printWarning(fileName, err)
 	}
+	return opts
 }
 
-func validateJSONArray(reader *bufio.Reader) bool {
-	nextExpectedDelims := []rune{'['}
-	for {
-		iterateOverContinuousWhitespace(reader)
-		r, _, err := reader.ReadRune()
-		if err != nil {
-			return false
-		}
-		if !slices.Contains(nextExpectedDelims, r) {
-			return false
-		}
-		if r == '[' {
-			iterateOverContinuousWhitespace(reader)
-			r, _, err = reader.ReadRune()
-			if err != nil {
-				return false
-			}
-			if r == ']' {
-				return true
-			}
-			_ = reader.UnreadRune()
-			if !validateJSONValue(reader) {
-				return false
-			}
-			nextExpectedDelims = []rune{']', ','}
-		} else if r == ']' {
-			return true
-		} else if r == ',' {
-			nextExpectedDelims = []rune{',', ']'}
-			if !validateJSONValue(reader) {
-				return false
-			}
-		}
-	}
+// printError reports a syntax error compiler-style as file:line:col: message.
+func printError(fileName string, err error) {
+	printDiagnostic(fileName, 0, "", err)
 }
 
-func validateJSONValue(reader *bufio.Reader) bool {
-	/*
-		Rules for a json value:
-			1. JSON value can be a string, number, boolean, null, object, array
-			2. JSON value can be enclosed in double quotes
-	*/
-	for {
-		iterateOverContinuousWhitespace(reader)
-		r, _, err := reader.ReadRune()
-		if err != nil {
-			return false
+func printWarning(fileName string, err error) {
+	printDiagnostic(fileName, 0, "warning: ", err)
+}
+
+// printLineError reports an error in the record on the given line of a
+// newline-delimited file.
+func printLineError(fileName string, line int, err error) {
+	printDiagnostic(fileName, line, "", err)
+}
+
+func printLineWarning(fileName string, line int, err error) {
+	printDiagnostic(fileName, line, "warning: ", err)
+}
+
+// printDiagnostic writes err to stderr. A non-zero line replaces the line
+// of a syntax error, which is relative to the record for newline-delimited
+// input. With -q only errors reading the input are written.
+func printDiagnostic(fileName string, line int, prefix string, err error) {
+	if quiet && exitCode(err) != 2 {
+		return
+	}
+	if fileName == "-" {
+		fileName = "<stdin>"
+	}
+	if list, ok := err.(parser.ErrorList); ok {
+		for _, e := range list {
+			printDiagnostic(fileName, line, prefix, e)
 		}
-		if r == '{' {
-			_ = reader.UnreadRune()
-			return validateJSONObject(reader)
-		} else if r == '[' {
-			_ = reader.UnreadRune()
-			return validateJSONArray(reader)
-		} else if r == '"' {
-			return validateString(reader)
-		} else if r == 'n' {
-			return validateSequence(reader, []int32{'u', 'l', 'l'})
-		} else if unicode.IsDigit(r) || r == '-' {
-			_ = reader.UnreadRune()
-			return validateNumber(reader)
-		} else if r == 't' {
-			return validateSequence(reader, []int32{'r', 'u', 'e'})
-		} else if r == 'f' {
-			return validateSequence(reader, []int32{'a', 'l', 's', 'e'})
-		} else {
-			return false
+		return
+	}
+	if pos, msg, ok := diagnosticPosition(err); ok {
+		if line == 0 {
+			line = pos.Line
 		}
+		fmt.Fprintf(os.Stderr, "%s:%d:%d: %s%s\n", fileName, line, pos.Column, prefix, msg)
+		return
 	}
+	if line != 0 {
+		fmt.Fprintf(os.Stderr, "%s:%d: %s%s\n", fileName, line, prefix, err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s: %s%s\n", fileName, prefix, err)
 }
 
-func validateNumber(reader *bufio.Reader) bool {
-	isFirstDigit := true
-	isLeadingZero := false
-	signSeen := false
-	for {
-		r, _, err := reader.ReadRune()
-		if err != nil {
-			// EOF is valid only if we've consumed at least one digit
-			if err == io.EOF {
-				return !isFirstDigit
-			}
-			return false
-		}
-		if !unicode.IsDigit(r) {
-			// Only a single leading '-' is allowed
-			if r == '-' {
-				if !isFirstDigit || signSeen {
-					return false
-				}
-				signSeen = true
-				continue
-			}
-			// No '+' allowed at any position in JSON numbers
-			if r == '+' {
-				return false
-			}
-			if isFirstDigit {
-				return false
-			}
-			// If the number is a float, then it can have a single decimal point before the exponent
-			if r == '.' {
-				return validateDecimalPart(reader)
-			} else if r == 'e' || r == 'E' {
-				return validateExponentPart(reader)
-			}
-			_ = reader.UnreadRune()
-			return true
-		}
-		if r == '0' && isFirstDigit {
-			isLeadingZero = true
-		}
-		if isLeadingZero && !isFirstDigit {
-			// No digits allowed after a leading zero unless followed by . or exponent (handled above)
-			return false
-		}
-		isFirstDigit = false
+// diagnosticPosition returns the input position and message of errors that
+// point at a place in the document.
+func diagnosticPosition(err error) (parser.Position, string, bool) {
+	var syntaxErr *parser.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return syntaxErr.Position, syntaxErr.Message(), true
 	}
+	var limitErr *parser.LimitError
+	if errors.As(err, &limitErr) {
+		return limitErr.Position, limitErr.Message(), true
+	}
+	return parser.Position{}, "", false
 }
 
-func validateDecimalPart(reader *bufio.Reader) bool {
-	isFirstDigit := true
-	for {
-		r, _, err := reader.ReadRune()
-		if err != nil {
-			if err == io.EOF {
-				// Valid only if we saw at least one digit after the decimal point
-				return !isFirstDigit
-			}
-			return false
-		}
-		if !unicode.IsDigit(r) {
-			// After decimal point, first character should be a digit
-			if isFirstDigit {
-				return false
-			}
-			if r == 'e' || r == 'E' {
-				return validateExponentPart(reader)
-			}
-			_ = reader.UnreadRune()
-			return true
-		}
-		isFirstDigit = false
+// openInput opens fileName for reading, or standard input for "-".
+func openInput(fileName string) (io.ReadCloser, error) {
+	if fileName == "-" {
+		return io.NopCloser(os.Stdin), nil
 	}
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	return f, nil
 }
 
-func validateExponentPart(reader *bufio.Reader) bool {
-	isFirstDigit := true
-	signSeen := false
-	for {
-		r, _, err := reader.ReadRune()
-		if err != nil {
-			if err == io.EOF {
-				// Must have at least one digit in the exponent
-				return !isFirstDigit
-			}
-			return false
-		}
-		if !unicode.IsDigit(r) {
-			if r == '+' || r == '-' {
-				// Allow at most one sign and only before the first digit
-				if !isFirstDigit || signSeen {
-					return false
-				}
-				signSeen = true
-				continue
-			}
-			if isFirstDigit {
-				return false
-			}
-			_ = reader.UnreadRune()
-			return true
-		}
-		isFirstDigit = false
+// writeOutput writes data back to fileName with -w and to stdout otherwise.
+// Standard input cannot be written back, so "-" always goes to stdout.
+func writeOutput(fileName string, data []byte, write bool) error {
+	if write && fileName != "-" {
+		return os.WriteFile(fileName, data, 0o644)
 	}
+	_, err := os.Stdout.Write(data)
+	return err
 }
 
-func validateSequence(reader *bufio.Reader, seq []int32) bool {
-	nextCharIndex := 0
-	for {
-		r, _, err := reader.ReadRune()
-		if err != nil {
-			return false
-		}
-		if r != seq[nextCharIndex] {
-			return false
-		}
-		nextCharIndex += 1
-		if nextCharIndex == len(seq) {
-			return true
-		}
+func validateJSONFromFile(fileName string, opts parser.Options) error {
+	f, err := openInput(fileName)
+	if err != nil {
+		return err
 	}
+	defer f.Close()
+	reader := bufio.NewReader(f)
+	return validateJSON(reader, withWarnings(opts, fileName))
 }
 
-func validateString(reader *bufio.Reader) bool {
-	// Called after the opening double quote (") has been consumed.
-	for {
-		r, _, err := reader.ReadRune()
-		if err != nil {
-			return false
-		}
-		// Closing quote (not escaped) ends the string
-		if r == '"' {
-			return true
-		}
-		// Unescaped control characters (U+0000 through U+001F) are not allowed in JSON strings
-		if r < 0x20 {
-			return false
-		}
-		if r == '\\' {
-			// Validate escape sequence
-			esc, _, err := reader.ReadRune()
-			if err != nil {
-				return false
-			}
-			switch esc {
-			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
-				// Valid simple escapes
-			case 'u':
-				// Expect exactly four hex digits
-				for i := 0; i < 4; i++ {
-					h, _, err := reader.ReadRune()
-					if err != nil {
-						return false
-					}
-					if !isHexDigit(h) {
-						return false
-					}
-				}
-			default:
-				return false
-			}
-			// Continue scanning characters after a valid escape sequence
-			continue
-		}
-		// Any other rune is fine (already filtered out control chars)
+// parseFile reads the whole document in fileName into a value tree.
+func parseFile(fileName string, opts parser.Options) (parser.Value, error) {
+	f, err := openInput(fileName)
+	if err != nil {
+		return nil, err
 	}
+	defer f.Close()
+	return parser.ParseWithOptions(bufio.NewReader(f), withWarnings(opts, fileName))
 }
 
-func iterateOverContinuousWhitespace(reader *bufio.Reader) {
+// validateJSON checks the document in reader token by token without
+// building its value tree, so memory use does not grow with its size.
+func validateJSON(reader io.Reader, opts parser.Options) error {
+	d := parser.NewDecoderWithOptions(reader, opts)
 	for {
-		r, _, err := reader.ReadRune()
-		if err != nil {
-			return
-		}
-		if unicode.IsSpace(r) {
-			continue
+		if _, err := d.Token(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
 		}
-		_ = reader.UnreadRune()
-		break
 	}
 }
-
-// isHexDigit reports whether r is a hexadecimal digit.
-func isHexDigit(r rune) bool {
-	return (r >= '0' && r <= '9') ||
-		(r >= 'a' && r <= 'f') ||
-		(r >= 'A' && r <= 'F')
-}
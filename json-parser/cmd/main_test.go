@@ -1,6 +1,7 @@
 package main
 
 import (
+	"json-parser/parser"
 	"testing"
 )
 
@@ -62,9 +63,30 @@ func TestValidateJSONFromFile_Table(t *testing.T) {
 	for _, tc := range tests {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
-			isValid := validateJSONFromFile(tc.file)
-			if isValid != tc.want {
-				t.Fatalf("unexpected output.\nexpected: %v\nactual: %v", tc.want, isValid)
+			err := validateJSONFromFile(tc.file, parser.Options{})
+			if isValid := err == nil; isValid != tc.want {
+				t.Fatalf("unexpected output.\nexpected: %v\nactual: %v (%v)", tc.want, isValid, err)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want int
+	}{
+		{"Valid", "../test_files/step1/valid.json", 0},
+		{"Invalid", "../test_files/step1/invalid.json", 1},
+		{"TooDeep", "../test_files/step4/valid.json", 1},
+		{"Missing", "../test_files/missing.json", 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateJSONFromFile(tc.file, parser.Options{MaxDepth: 1})
+			if got := exitCode(err); got != tc.want {
+				t.Fatalf("unexpected exit code.\nexpected: %d\nactual: %d (%v)", tc.want, got, err)
 			}
 		})
 	}
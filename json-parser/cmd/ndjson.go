@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"json-parser/parser"
+)
+
+// ndjsonSummary counts the records of a newline-delimited JSON input.
+type ndjsonSummary struct {
+	passed int
+	failed int
+}
+
+func validateNDJSONFromFile(fileName string, opts parser.Options) (ndjsonSummary, error) {
+	f, err := openInput(fileName)
+	if err != nil {
+		return ndjsonSummary{}, err
+	}
+	defer f.Close()
+	reader := bufio.NewReader(f)
+	return validateNDJSON(reader, opts, func(line int, err error) {
+		printLineError(fileName, line, err)
+	}, func(line int, err error) {
+		printLineWarning(fileName, line, err)
+	})
+}
+
+// validateNDJSON validates every line of reader as a separate JSON document
+// and calls onError with the 1-based line number of each invalid record,
+// and onWarning for each warning. Blank lines are skipped and not counted
+// as records. opts.MaxDocumentSize applies to each record on its own.
+func validateNDJSON(reader *bufio.Reader, opts parser.Options, onError, onWarning func(line int, err error)) (ndjsonSummary, error) {
+	var summary ndjsonSummary
+	lineNo := 0
+	for {
+		line, err := readRecord(reader, opts.MaxDocumentSize)
+		if err != nil && err != io.EOF {
+			return summary, err
+		}
+		if len(line) > 0 {
+			lineNo++
+			opts.Warn = func(err error) {
+				onWarning(lineNo, err)
+			}
+			if len(bytes.TrimSpace(line)) > 0 {
+				if perr := validateJSON(bytes.NewReader(line), opts); perr != nil {
+					summary.failed++
+					onError(lineNo, perr)
+				} else {
+					summary.passed++
+				}
+			}
+		}
+		if err == io.EOF {
+			return summary, nil
+		}
+	}
+}
+
+// readRecord reads a line of reader. If limit is positive, only the first
+// limit+1 bytes of the line are kept, enough for the parser to report the
+// record as too large without holding all of it in memory.
+func readRecord(reader *bufio.Reader, limit int64) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if limit > 0 {
+			chunk = chunk[:min(int64(len(chunk)), max(0, limit+1-int64(len(line))))]
+		}
+		line = append(line, chunk...)
+		if err != bufio.ErrBufferFull {
+			return line, err
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"json-parser/parser"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateNDJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		want       ndjsonSummary
+		wantErrors []int
+	}{
+		{"Valid", "../test_files/ndjson/valid.ndjson", ndjsonSummary{passed: 2}, nil},
+		{"Mixed", "../test_files/ndjson/mixed.ndjson", ndjsonSummary{passed: 3, failed: 2}, []int{4, 6}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := os.Open(tc.file)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer f.Close()
+			var errorLines []int
+			summary, err := validateNDJSON(bufio.NewReader(f), parser.Options{}, func(line int, err error) {
+				errorLines = append(errorLines, line)
+			}, func(line int, err error) {})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if summary != tc.want {
+				t.Fatalf("unexpected summary.\nexpected: %+v\nactual: %+v", tc.want, summary)
+			}
+			if !slices.Equal(errorLines, tc.wantErrors) {
+				t.Fatalf("unexpected error lines.\nexpected: %v\nactual: %v", tc.wantErrors, errorLines)
+			}
+		})
+	}
+}
+
+func TestValidateNDJSONMaxSize(t *testing.T) {
+	input := "[1]\n[\"" + strings.Repeat("a", 1<<20) + "\"]\n{}\n"
+	var errs []error
+	summary, err := validateNDJSON(bufio.NewReaderSize(strings.NewReader(input), 16), parser.Options{MaxDocumentSize: 8}, func(line int, err error) {
+		errs = append(errs, err)
+	}, func(line int, err error) {})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (ndjsonSummary{passed: 2, failed: 1}); summary != want {
+		t.Fatalf("unexpected summary.\nexpected: %+v\nactual: %+v", want, summary)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], parser.ErrLimitExceeded) {
+		t.Fatalf("expected a limit error for the long record, got %v", errs)
+	}
+
+	line, err := readRecord(bufio.NewReaderSize(strings.NewReader(input[4:]), 16), 8)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(line) != 9 {
+		t.Fatalf("expected the record to be cut at 9 bytes, got %d", len(line))
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"json-parser/parser"
+	"json-parser/patch"
+	"os"
+	"strings"
+)
+
+func runPatch(args []string) int {
+	fs := flag.NewFlagSet("patch", flag.ExitOnError)
+	merge := fs.Bool("merge", false, "treat the patch as an RFC 7386 JSON Merge Patch instead of an RFC 6902 JSON Patch")
+	indent := fs.Int("indent", 2, "number of spaces per level of nesting, 0 to print the result on one line")
+	write := fs.Bool("w", false, "write the result back to the file instead of stdout")
+	pf := addParserFlags(fs)
+	_ = fs.Parse(args)
+	if fs.NArg() != 2 {
+		fmt.Println("Usage: json-parser patch [-merge] [-indent n] [-w] <patch.json> <file>")
+		return 2
+	}
+	opts, err := pf.options()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *write && opts.Dialect != parser.DialectJSON {
+		fmt.Fprintln(os.Stderr, "-w would drop the comments of jsonc and json5 input, write to stdout instead")
+		return 2
+	}
+	patchFile, fileName := fs.Arg(0), fs.Arg(1)
+	patchDoc, err := parseFile(patchFile, parser.Options{RFC: parser.RFC8259})
+	if err != nil {
+		printError(patchFile, err)
+		return 2
+	}
+	doc, err := parseFile(fileName, opts)
+	if err != nil {
+		printError(fileName, err)
+		return exitCode(err)
+	}
+
+	if *merge {
+		doc = patch.Merge(doc, patchDoc)
+	} else if doc, err = patch.Apply(doc, patchDoc); err != nil {
+		printError(patchFile, err)
+		return 1
+	}
+	var buf bytes.Buffer
+	if err := parser.WriteValue(&buf, doc, strings.Repeat(" ", max(*indent, 0))); err != nil {
+		printError(fileName, err)
+		return 1
+	}
+	buf.WriteByte('\n')
+	if err := writeOutput(fileName, buf.Bytes(), *write); err != nil {
+		printError(fileName, err)
+		return exitCode(err)
+	}
+	return 0
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"json-parser/jsonpath"
+	"json-parser/parser"
+	"os"
+)
+
+func runQuery(args []string) int {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	asArray := fs.Bool("array", false, "print the matches as a single JSON array instead of one per line")
+	pf := addParserFlags(fs)
+	_ = fs.Parse(args)
+	if fs.NArg() < 2 {
+		fmt.Println("Usage: json-parser query [-array] <jsonpath> <file>")
+		return 2
+	}
+	opts, err := pf.options()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	path, err := jsonpath.Compile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	fileName := fs.Arg(1)
+	doc, err := parseFile(fileName, opts)
+	if err != nil {
+		printError(fileName, err)
+		return exitCode(err)
+	}
+
+	matches := path.Evaluate(doc)
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	if *asArray {
+		out.Write(parser.Marshal(&parser.Array{Elements: matches}))
+		out.WriteByte('\n')
+		return 0
+	}
+	for _, m := range matches {
+		out.Write(parser.Marshal(m))
+		out.WriteByte('\n')
+	}
+	return 0
+}
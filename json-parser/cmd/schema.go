@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"json-parser/parser"
+	"json-parser/schema"
+	"os"
+)
+
+// printViolations writes each schema violation to stderr as
+// file#pointer: message unless -q is set.
+func printViolations(fileName string, violations []schema.Violation) {
+	if quiet {
+		return
+	}
+	for _, v := range violations {
+		fmt.Fprintf(os.Stderr, "%s#%s: %s\n", fileName, v.Path, v.Message)
+	}
+}
+
+// validateWithSchema checks fileName against the schema in schemaFile and
+// prints each violation as file#pointer: message. It returns the exit code.
+func validateWithSchema(fileName string, schemaFile string, opts parser.Options) int {
+	schemaDoc, err := parseFile(schemaFile, parser.Options{})
+	if err != nil {
+		printError(schemaFile, err)
+		return 2
+	}
+	s, err := schema.Compile(schemaDoc)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", schemaFile, err)
+		return 2
+	}
+	doc, err := parseFile(fileName, opts)
+	if err != nil {
+		printError(fileName, err)
+		if exitCode(err) == 1 {
+			printStatus("Invalid JSON")
+		}
+		return exitCode(err)
+	}
+	violations := s.Validate(doc)
+	if len(violations) > 0 {
+		printViolations(fileName, violations)
+		printStatus(fmt.Sprintf("%d schema violations", len(violations)))
+		return 1
+	}
+	printStatus("Valid JSON")
+	return 0
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"json-parser/parser"
+	"json-parser/pointer"
+	"json-parser/stats"
+	"os"
+)
+
+// runStats prints a profile of a document: its nesting depth, how many
+// values of each kind it has and where its bytes go.
+func runStats(args []string) int {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	top := fs.Int("top", 10, "number of arrays, strings and keys to list")
+	pf := addParserFlags(fs)
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Println("Usage: json-parser stats [-top n] <file>")
+		return 2
+	}
+	opts, err := pf.options()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	fileName := fs.Arg(0)
+	f, err := openInput(fileName)
+	if err != nil {
+		printError(fileName, err)
+		return 2
+	}
+	defer f.Close()
+	s, err := stats.Collect(parser.NewDecoderWithOptions(bufio.NewReader(f), withWarnings(opts, fileName)), max(*top, 0))
+	if err != nil {
+		printError(fileName, err)
+		return exitCode(err)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	fmt.Fprintf(out, "size:      %d bytes\n", s.Size)
+	fmt.Fprintf(out, "max depth: %d\n", s.MaxDepth)
+	fmt.Fprintf(out, "objects:   %d\n", s.Objects)
+	fmt.Fprintf(out, "arrays:    %d\n", s.Arrays)
+	fmt.Fprintf(out, "strings:   %d\n", s.Strings)
+	fmt.Fprintf(out, "numbers:   %d\n", s.Numbers)
+	fmt.Fprintf(out, "booleans:  %d\n", s.Bools)
+	fmt.Fprintf(out, "nulls:     %d\n", s.Nulls)
+	printEntries(out, "largest arrays (elements)", s.LargestArrays)
+	printEntries(out, "longest strings (bytes)", s.LongestStrings)
+	if len(s.Keys) > 0 {
+		fmt.Fprintln(out, "\nkeys (occurrences):")
+		for _, k := range s.Keys[:min(len(s.Keys), *top)] {
+			fmt.Fprintf(out, "%10d  %q\n", k.Count, k.Key)
+		}
+	}
+	printEntries(out, "top-level members (bytes)", s.Members)
+	return 0
+}
+
+func printEntries(out *bufio.Writer, title string, entries []stats.Entry) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "\n%s:\n", title)
+	for _, e := range entries {
+		fmt.Fprintf(out, "%10d  %s\n", e.Size, displayPointer(e.Path))
+	}
+}
+
+// displayPointer shows the root, whose pointer is empty, as "(root)".
+func displayPointer(p pointer.Pointer) string {
+	if len(p) == 0 {
+		return "(root)"
+	}
+	return p.String()
+}
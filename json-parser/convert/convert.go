@@ -0,0 +1,43 @@
+// Package convert writes parsed documents as YAML, TOML or CSV and reads
+// CSV back into the parser's value model.
+package convert
+
+import (
+	"json-parser/parser"
+	"strings"
+)
+
+// members returns the members of o with duplicate keys collapsed into the
+// first position holding the last value, matching (*parser.Object).Get.
+func members(o *parser.Object) []parser.Member {
+	index := make(map[string]int, len(o.Members))
+	var out []parser.Member
+	for _, m := range o.Members {
+		if i, ok := index[m.Key.Value]; ok {
+			out[i].Value = m.Value
+			continue
+		}
+		index[m.Key.Value] = len(out)
+		out = append(out, m)
+	}
+	return out
+}
+
+// isEmptyContainer reports whether v is an object or array without
+// members, which the block styles of YAML cannot express.
+func isEmptyContainer(v parser.Value) bool {
+	switch v := v.(type) {
+	case *parser.Object:
+		return len(v.Members) == 0
+	case *parser.Array:
+		return len(v.Elements) == 0
+	}
+	return false
+}
+
+// quote returns s as a double-quoted string using JSON escapes, which YAML
+// and TOML basic strings both understand.
+func quote(s string) string {
+	// DEL is allowed raw in JSON but not in TOML.
+	return `"` + strings.ReplaceAll(parser.NewString(s).Raw, "\x7f", `\u007f`) + `"`
+}
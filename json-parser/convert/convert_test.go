@@ -0,0 +1,123 @@
+package convert
+
+import (
+	"bytes"
+	"json-parser/parser"
+	"strings"
+	"testing"
+)
+
+func TestWriteYAML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"scalar", `"a: b"`, "\"a: b\"\n"},
+		{"empty", `{}`, "{}\n"},
+		{"object", `{"a": 1, "b": "yes", "c": null, "d": [], "e": "x y"}`, "a: 1\nb: \"yes\"\nc: null\nd: []\ne: x y\n"},
+		{"nested", `{"a": {"b": [1, {"c": true, "d": "1"}]}}`, "a:\n  b:\n    - 1\n    - c: true\n      d: \"1\"\n"},
+		{"array of arrays", `[[1, 2], []]`, "- - 1\n  - 2\n- []\n"},
+		{"duplicate keys", `{"a": 1, "b": 2, "a": 3}`, "a: 3\nb: 2\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := parser.ParseWithOptions(strings.NewReader(tc.input), parser.Options{RFC: parser.RFC8259})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := WriteYAML(&buf, doc); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buf.String() != tc.want {
+				t.Fatalf("unexpected output.\nexpected:\n%s\nactual:\n%s", tc.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestWriteTOML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		err   string
+	}{
+		{
+			name:  "tables",
+			input: `{"t": {"x": 1}, "a": "s\u007f", "my key": [1.5, {"b": false}]}`,
+			want:  "a = \"s\\u007f\"\n\"my key\" = [1.5, { b = false }]\n\n[t]\nx = 1\n",
+		},
+		{
+			name:  "array of tables",
+			input: `{"p": [{"n": 1, "q": {"r": 2}}, {"n": 3}]}`,
+			want:  "[[p]]\nn = 1\n\n[p.q]\nr = 2\n\n[[p]]\nn = 3\n",
+		},
+		{name: "not an object", input: `[1]`, err: "TOML needs an object at the top level, found array"},
+		{name: "null", input: `{"a": [null]}`, err: "/a/0: TOML cannot represent null"},
+		{name: "big integer", input: `{"a": 9223372036854775808}`, err: "/a: integer 9223372036854775808 does not fit in a TOML integer"},
+		{name: "big float", input: `{"big": 1e999999999}`, err: "/big: number 1e999999999 does not fit in a TOML float"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := parser.Parse(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var buf bytes.Buffer
+			err = WriteTOML(&buf, doc)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("unexpected error.\nexpected: %s\nactual: %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buf.String() != tc.want {
+				t.Fatalf("unexpected output.\nexpected:\n%s\nactual:\n%s", tc.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	doc, err := parser.Parse(strings.NewReader(`[{"id": 1, "name": "a,b"}, {"name": "c\"d", "active": true}, {"id": null}]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, doc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "id,name,active\n1,\"a,b\",\n,\"c\"\"d\",true\n,,\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected CSV.\nexpected:\n%s\nactual:\n%s", want, buf.String())
+	}
+
+	arr, err := ReadCSV(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	back := `[{"id":"1","name":"a,b","active":""},{"id":"","name":"c\"d","active":"true"},{"id":"","name":"","active":""}]`
+	if actual := string(parser.Marshal(arr)); actual != back {
+		t.Fatalf("unexpected objects.\nexpected: %s\nactual: %s", back, actual)
+	}
+
+	for _, input := range []string{`{"a": 1}`, `[1]`, `[{"a": [1]}]`} {
+		doc, err := parser.Parse(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := WriteCSV(&buf, doc); err == nil {
+			t.Fatalf("expected an error for %s", input)
+		}
+	}
+	if _, err := ReadCSV(strings.NewReader("")); err == nil {
+		t.Fatalf("expected an error for CSV without a header")
+	}
+}
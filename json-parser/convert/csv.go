@@ -0,0 +1,100 @@
+package convert
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"json-parser/parser"
+)
+
+// WriteCSV writes an array of flat objects as CSV. The header row is the
+// union of all member names in order of first appearance; members missing
+// from an object and null values give empty cells.
+func WriteCSV(w io.Writer, v parser.Value) error {
+	arr, ok := v.(*parser.Array)
+	if !ok {
+		return fmt.Errorf("CSV needs an array of objects, found %s", v.Kind())
+	}
+	var header []string
+	column := make(map[string]int)
+	rows := make([][]parser.Member, 0, len(arr.Elements))
+	for i, e := range arr.Elements {
+		obj, ok := e.(*parser.Object)
+		if !ok {
+			return fmt.Errorf("/%d: CSV needs an array of objects, found %s", i, e.Kind())
+		}
+		ms := members(obj)
+		for _, m := range ms {
+			if k := m.Value.Kind(); k == parser.ObjectKind || k == parser.ArrayKind {
+				return fmt.Errorf("/%d: member %q is an %s, only flat objects fit in CSV", i, m.Key.Value, k)
+			}
+			if _, ok := column[m.Key.Value]; !ok {
+				column[m.Key.Value] = len(header)
+				header = append(header, m.Key.Value)
+			}
+		}
+		rows = append(rows, ms)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, ms := range rows {
+		record := make([]string, len(header))
+		for _, m := range ms {
+			record[column[m.Key.Value]] = csvCell(m.Value)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvCell(v parser.Value) string {
+	switch v := v.(type) {
+	case parser.String:
+		return v.Value
+	case parser.Number:
+		return v.Literal
+	case parser.Null:
+		return ""
+	}
+	return string(parser.Marshal(v))
+}
+
+// ReadCSV reads CSV with a header row and returns an array holding one
+// object per record. Every cell becomes a string member named after its
+// column.
+func ReadCSV(r io.Reader) (*parser.Array, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV input has no header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]parser.String, len(header))
+	for i, name := range header {
+		keys[i] = parser.NewString(name)
+	}
+	arr := &parser.Array{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return arr, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		obj := &parser.Object{Members: make([]parser.Member, len(record))}
+		for i, cell := range record {
+			obj.Members[i] = parser.Member{Key: keys[i], Value: parser.NewString(cell)}
+		}
+		arr.Elements = append(arr.Elements, obj)
+	}
+}
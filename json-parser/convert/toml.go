@@ -0,0 +1,171 @@
+package convert
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"json-parser/parser"
+	"json-parser/pointer"
+	"regexp"
+	"strings"
+)
+
+// WriteTOML writes v, which must be an object, as a TOML document. Nested
+// objects become tables and arrays of objects become arrays of tables.
+// TOML has no null, so null values are an error, and integers must fit in
+// an int64.
+func WriteTOML(w io.Writer, v parser.Value) error {
+	obj, ok := v.(*parser.Object)
+	if !ok {
+		return fmt.Errorf("TOML needs an object at the top level, found %s", v.Kind())
+	}
+	t := &tomlWriter{w: bufio.NewWriter(w)}
+	if err := t.table(obj, nil, pointer.Pointer{}); err != nil {
+		return err
+	}
+	return t.w.Flush()
+}
+
+type tomlWriter struct {
+	w *bufio.Writer
+	// wrote is set once anything has been written, to separate tables with
+	// a blank line.
+	wrote bool
+}
+
+// table writes the key/value pairs of obj followed by its sub-tables.
+// keys is the dotted name of obj, path its location for error messages.
+func (t *tomlWriter) table(obj *parser.Object, keys []string, path pointer.Pointer) error {
+	ms := members(obj)
+	for _, m := range ms {
+		if isTable(m.Value) || isTableArray(m.Value) {
+			continue
+		}
+		s, err := tomlValue(m.Value, path.Append(m.Key.Value))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(t.w, "%s = %s\n", tomlKey(m.Key.Value), s)
+		t.wrote = true
+	}
+	for _, m := range ms {
+		sub := append(keys[:len(keys):len(keys)], m.Key.Value)
+		at := path.Append(m.Key.Value)
+		switch {
+		case isTable(m.Value):
+			t.header("[" + tomlKeys(sub) + "]")
+			if err := t.table(m.Value.(*parser.Object), sub, at); err != nil {
+				return err
+			}
+		case isTableArray(m.Value):
+			for i, e := range m.Value.(*parser.Array).Elements {
+				t.header("[[" + tomlKeys(sub) + "]]")
+				if err := t.table(e.(*parser.Object), sub, at.AppendIndex(i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (t *tomlWriter) header(h string) {
+	if t.wrote {
+		t.w.WriteByte('\n')
+	}
+	t.w.WriteString(h + "\n")
+	t.wrote = true
+}
+
+func isTable(v parser.Value) bool {
+	return v.Kind() == parser.ObjectKind
+}
+
+// isTableArray reports whether v is a non-empty array holding only objects.
+func isTableArray(v parser.Value) bool {
+	arr, ok := v.(*parser.Array)
+	if !ok || len(arr.Elements) == 0 {
+		return false
+	}
+	for _, e := range arr.Elements {
+		if !isTable(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// tomlValue formats v inline, using inline tables for objects nested in
+// arrays.
+func tomlValue(v parser.Value, path pointer.Pointer) (string, error) {
+	switch v := v.(type) {
+	case *parser.Object:
+		parts := make([]string, 0, len(v.Members))
+		for _, m := range members(v) {
+			s, err := tomlValue(m.Value, path.Append(m.Key.Value))
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, tomlKey(m.Key.Value)+" = "+s)
+		}
+		if len(parts) == 0 {
+			return "{}", nil
+		}
+		return "{ " + strings.Join(parts, ", ") + " }", nil
+	case *parser.Array:
+		parts := make([]string, 0, len(v.Elements))
+		for i, e := range v.Elements {
+			s, err := tomlValue(e, path.AppendIndex(i))
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, s)
+		}
+		return "[" + strings.Join(parts, ", ") + "]", nil
+	case parser.String:
+		return quote(v.Value), nil
+	case parser.Number:
+		return tomlNumber(v, path)
+	case parser.Bool:
+		return string(parser.Marshal(v)), nil
+	}
+	return "", fmt.Errorf("%s: TOML cannot represent %s", path, v.Kind())
+}
+
+func tomlNumber(n parser.Number, path pointer.Pointer) (string, error) {
+	switch n.Literal {
+	case "Infinity":
+		return "inf", nil
+	case "-Infinity":
+		return "-inf", nil
+	case "NaN":
+		return "nan", nil
+	}
+	if strings.ContainsAny(n.Literal, ".eE") {
+		if _, err := n.Float64(); err != nil {
+			return "", fmt.Errorf("%s: number %s does not fit in a TOML float", path, n.Literal)
+		}
+		return n.Literal, nil
+	}
+	if _, err := n.Int64(); err != nil {
+		return "", fmt.Errorf("%s: integer %s does not fit in a TOML integer", path, n.Literal)
+	}
+	return n.Literal, nil
+}
+
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func tomlKey(k string) string {
+	if bareKey.MatchString(k) {
+		return k
+	}
+	return quote(k)
+}
+
+func tomlKeys(keys []string) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = tomlKey(k)
+	}
+	return strings.Join(parts, ".")
+}
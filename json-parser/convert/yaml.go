@@ -0,0 +1,101 @@
+package convert
+
+import (
+	"io"
+	"json-parser/parser"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// WriteYAML writes v as a YAML document using block style for non-empty
+// objects and arrays.
+func WriteYAML(w io.Writer, v parser.Value) error {
+	var lines []string
+	if isEmptyContainer(v) || (v.Kind() != parser.ObjectKind && v.Kind() != parser.ArrayKind) {
+		lines = []string{yamlScalar(v)}
+	} else {
+		lines = yamlBlock(v)
+	}
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// yamlBlock returns the lines of a non-empty object or array.
+func yamlBlock(v parser.Value) []string {
+	var lines []string
+	switch v := v.(type) {
+	case *parser.Object:
+		for _, m := range members(v) {
+			key := yamlString(m.Key.Value)
+			if isBlock(m.Value) {
+				lines = append(lines, key+":")
+				for _, l := range yamlBlock(m.Value) {
+					lines = append(lines, "  "+l)
+				}
+				continue
+			}
+			lines = append(lines, key+": "+yamlScalar(m.Value))
+		}
+	case *parser.Array:
+		for _, e := range v.Elements {
+			if !isBlock(e) {
+				lines = append(lines, "- "+yamlScalar(e))
+				continue
+			}
+			// Nested blocks start on the line of the dash.
+			for i, l := range yamlBlock(e) {
+				if i == 0 {
+					lines = append(lines, "- "+l)
+				} else {
+					lines = append(lines, "  "+l)
+				}
+			}
+		}
+	}
+	return lines
+}
+
+func isBlock(v parser.Value) bool {
+	k := v.Kind()
+	return (k == parser.ObjectKind || k == parser.ArrayKind) && !isEmptyContainer(v)
+}
+
+// yamlScalar formats a scalar or an empty container in flow style.
+func yamlScalar(v parser.Value) string {
+	switch v := v.(type) {
+	case *parser.Object:
+		return "{}"
+	case *parser.Array:
+		return "[]"
+	case parser.String:
+		return yamlString(v.Value)
+	case parser.Number:
+		switch v.Literal {
+		case "Infinity":
+			return ".inf"
+		case "-Infinity":
+			return "-.inf"
+		case "NaN":
+			return ".nan"
+		}
+		return v.Literal
+	}
+	return string(parser.Marshal(v))
+}
+
+var (
+	plainYAML = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_ ./-]*$`)
+	// yamlKeywords are plain scalars that YAML 1.1 parsers read as
+	// booleans or null.
+	yamlKeywords = []string{"y", "yes", "n", "no", "true", "false", "on", "off", "null"}
+)
+
+// yamlString writes s unquoted when a YAML parser would read it back as the
+// same string, and double-quoted otherwise.
+func yamlString(s string) string {
+	if plainYAML.MatchString(s) && !strings.HasSuffix(s, " ") && !slices.Contains(yamlKeywords, strings.ToLower(s)) {
+		return s
+	}
+	return quote(s)
+}
@@ -0,0 +1,136 @@
+// Package diff compares parsed documents structurally. Member order and
+// formatting are ignored and numbers are compared by value.
+package diff
+
+import (
+	"json-parser/parser"
+	"json-parser/pointer"
+)
+
+// Op is the kind of a Change.
+type Op int
+
+const (
+	Added Op = iota
+	Removed
+	Changed
+)
+
+func (op Op) String() string {
+	switch op {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	}
+	return "unknown"
+}
+
+// Change is a single difference between two documents. Old is nil for
+// Added and New is nil for Removed.
+type Change struct {
+	Op   Op
+	Path pointer.Pointer
+	Old  parser.Value
+	New  parser.Value
+}
+
+// Compare returns the changes that turn a into b, or nil if they are equal.
+// Objects are compared member by member and arrays element by element, so a
+// value inserted in the middle of an array shows up as a change of every
+// following element.
+func Compare(a, b parser.Value) []Change {
+	return compare(a, b, pointer.Pointer{}, nil)
+}
+
+func compare(a, b parser.Value, path pointer.Pointer, out []Change) []Change {
+	switch a := a.(type) {
+	case *parser.Object:
+		if b, ok := b.(*parser.Object); ok {
+			return compareObjects(a, b, path, out)
+		}
+	case *parser.Array:
+		if b, ok := b.(*parser.Array); ok {
+			return compareArrays(a, b, path, out)
+		}
+	}
+	if parser.Equal(a, b) {
+		return out
+	}
+	return append(out, Change{Op: Changed, Path: path, Old: a, New: b})
+}
+
+func compareObjects(a, b *parser.Object, path pointer.Pointer, out []Change) []Change {
+	for _, key := range keys(a) {
+		old, _ := a.Get(key)
+		if v, ok := b.Get(key); ok {
+			out = compare(old, v, path.Append(key), out)
+		} else {
+			out = append(out, Change{Op: Removed, Path: path.Append(key), Old: old})
+		}
+	}
+	for _, key := range keys(b) {
+		if _, ok := a.Get(key); !ok {
+			v, _ := b.Get(key)
+			out = append(out, Change{Op: Added, Path: path.Append(key), New: v})
+		}
+	}
+	return out
+}
+
+func compareArrays(a, b *parser.Array, path pointer.Pointer, out []Change) []Change {
+	n := min(len(a.Elements), len(b.Elements))
+	for i := range n {
+		out = compare(a.Elements[i], b.Elements[i], path.AppendIndex(i), out)
+	}
+	// Removals go from the end so that the indices stay valid when the
+	// changes are applied in order.
+	for i := len(a.Elements) - 1; i >= n; i-- {
+		out = append(out, Change{Op: Removed, Path: path.AppendIndex(i), Old: a.Elements[i]})
+	}
+	for i := n; i < len(b.Elements); i++ {
+		out = append(out, Change{Op: Added, Path: path.AppendIndex(i), New: b.Elements[i]})
+	}
+	return out
+}
+
+// keys returns the distinct member names of o in order of appearance.
+func keys(o *parser.Object) []string {
+	seen := make(map[string]bool, len(o.Members))
+	var names []string
+	for _, m := range o.Members {
+		if !seen[m.Key.Value] {
+			seen[m.Key.Value] = true
+			names = append(names, m.Key.Value)
+		}
+	}
+	return names
+}
+
+// Patch converts changes to an RFC 6902 JSON Patch document. Applying it to
+// the first document passed to Compare yields the second.
+func Patch(changes []Change) *parser.Array {
+	patch := &parser.Array{}
+	for _, c := range changes {
+		op := &parser.Object{}
+		add := func(key string, v parser.Value) {
+			op.Members = append(op.Members, parser.Member{Key: parser.NewString(key), Value: v})
+		}
+		switch c.Op {
+		case Added:
+			add("op", parser.NewString("add"))
+		case Removed:
+			add("op", parser.NewString("remove"))
+		case Changed:
+			add("op", parser.NewString("replace"))
+		}
+		add("path", parser.NewString(c.Path.String()))
+		if c.Op != Removed {
+			add("value", c.New)
+		}
+		patch.Elements = append(patch.Elements, op)
+	}
+	return patch
+}
@@ -0,0 +1,63 @@
+package diff
+
+import (
+	"json-parser/parser"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []string
+	}{
+		{"equal", `{"a": 1, "b": [true]}`, `{"b": [true], "a": 1.0}`, nil},
+		{"changed member", `{"a": 1}`, `{"a": 2}`, []string{"changed /a"}},
+		{"added and removed", `{"a": 1, "b": 2}`, `{"b": 2, "c~/": 3}`, []string{"removed /a", "added /c~0~1"}},
+		{"nested", `{"a": {"b": [1, 2]}}`, `{"a": {"b": [1, 3]}}`, []string{"changed /a/b/1"}},
+		{"shorter array", `[1, 2, 3]`, `[1]`, []string{"removed /2", "removed /1"}},
+		{"longer array", `[1]`, `[1, 2, 3]`, []string{"added /1", "added /2"}},
+		{"type change", `{"a": [1]}`, `{"a": {"0": 1}}`, []string{"changed /a"}},
+		{"root", `1`, `"1"`, []string{"changed "}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := parser.Options{RFC: parser.RFC8259}
+			a, err := parser.ParseWithOptions(strings.NewReader(tc.a), opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			b, err := parser.ParseWithOptions(strings.NewReader(tc.b), opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var actual []string
+			for _, c := range Compare(a, b) {
+				actual = append(actual, c.Op.String()+" "+c.Path.String())
+			}
+			if !slices.Equal(actual, tc.want) {
+				t.Fatalf("unexpected changes.\nexpected: %q\nactual: %q", tc.want, actual)
+			}
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	a, err := parser.Parse(strings.NewReader(`{"a": 1, "b": [1, 2, 3], "c": null}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := parser.Parse(strings.NewReader(`{"a": 2, "b": [1], "d": "x"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `[{"op":"replace","path":"/a","value":2},` +
+		`{"op":"remove","path":"/b/2"},{"op":"remove","path":"/b/1"},` +
+		`{"op":"remove","path":"/c"},{"op":"add","path":"/d","value":"x"}]`
+	if actual := string(parser.Marshal(Patch(Compare(a, b)))); actual != want {
+		t.Fatalf("unexpected patch.\nexpected: %s\nactual: %s", want, actual)
+	}
+}
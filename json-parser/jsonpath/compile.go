@@ -0,0 +1,424 @@
+package jsonpath
+
+import (
+	"fmt"
+	"json-parser/parser"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SyntaxError reports where a JSONPath expression could not be compiled.
+type SyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid JSONPath at offset %d: %s", e.Offset, e.Msg)
+}
+
+type compiler struct {
+	s   string
+	pos int
+}
+
+// Compile parses a JSONPath expression. Supported are child (.name,
+// ['name']), wildcard (*), index ([0], [-1]), slice ([start:end:step]),
+// union ([0,'a']), descendant (..) and filter ([?(@.price < 10)])
+// selectors. Filters may compare @ or $ paths and literals with ==, !=,
+// <, <=, > and >=, test a path for existence and combine conditions with
+// &&, || and !.
+func Compile(path string) (*Path, error) {
+	c := &compiler{s: path}
+	c.skipSpace()
+	if !c.consume("$") {
+		return nil, c.errorf("path must start with '$'")
+	}
+	segments, err := c.segments()
+	if err != nil {
+		return nil, err
+	}
+	c.skipSpace()
+	if c.pos < len(c.s) {
+		return nil, c.errorf("unexpected %q", c.s[c.pos:c.pos+1])
+	}
+	return &Path{segments: segments}, nil
+}
+
+// MustCompile is like Compile but panics if the expression is invalid.
+func MustCompile(path string) *Path {
+	p, err := Compile(path)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (c *compiler) segments() ([]segment, error) {
+	var segments []segment
+	for {
+		c.skipSpace()
+		switch {
+		case c.consume(".."):
+			var selectors []selector
+			var err error
+			switch {
+			case c.consume("*"):
+				selectors = []selector{wildcardSelector{}}
+			case c.peek() == '[':
+				selectors, err = c.bracket()
+			default:
+				var name string
+				name, err = c.name()
+				selectors = []selector{nameSelector(name)}
+			}
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, segment{descendant: true, selectors: selectors})
+		case c.consume("."):
+			if c.consume("*") {
+				segments = append(segments, segment{selectors: []selector{wildcardSelector{}}})
+				continue
+			}
+			name, err := c.name()
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, segment{selectors: []selector{nameSelector(name)}})
+		case c.peek() == '[':
+			selectors, err := c.bracket()
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, segment{selectors: selectors})
+		default:
+			return segments, nil
+		}
+	}
+}
+
+// name reads a member name written without quotes after '.' or '..'.
+func (c *compiler) name() (string, error) {
+	start := c.pos
+	for c.pos < len(c.s) {
+		b := c.s[c.pos]
+		if b == '_' || b == '-' || b >= 0x80 ||
+			(b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9') {
+			c.pos++
+			continue
+		}
+		break
+	}
+	if start == c.pos {
+		return "", c.errorf("expected member name")
+	}
+	return c.s[start:c.pos], nil
+}
+
+// bracket reads a [...] segment holding one or more comma-separated
+// selectors.
+func (c *compiler) bracket() ([]selector, error) {
+	c.pos++ // '['
+	var selectors []selector
+	for {
+		c.skipSpace()
+		sel, err := c.bracketSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, sel)
+		c.skipSpace()
+		if c.consume(",") {
+			continue
+		}
+		if c.consume("]") {
+			return selectors, nil
+		}
+		return nil, c.errorf("expected ',' or ']'")
+	}
+}
+
+func (c *compiler) bracketSelector() (selector, error) {
+	switch b := c.peek(); {
+	case b == '\'' || b == '"':
+		s, err := c.stringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return nameSelector(s), nil
+	case b == '*':
+		c.pos++
+		return wildcardSelector{}, nil
+	case b == '?':
+		c.pos++
+		cond, err := c.orExpr()
+		if err != nil {
+			return nil, err
+		}
+		return filterSelector{cond: cond}, nil
+	case b == '-' || b == ':' || (b >= '0' && b <= '9'):
+		return c.indexOrSlice()
+	}
+	return nil, c.errorf("expected selector")
+}
+
+func (c *compiler) indexOrSlice() (selector, error) {
+	start, err := c.optionalInt()
+	if err != nil {
+		return nil, err
+	}
+	c.skipSpace()
+	if !c.consume(":") {
+		if start == nil {
+			return nil, c.errorf("expected index")
+		}
+		return indexSelector(*start), nil
+	}
+	sel := sliceSelector{start: start, step: 1}
+	c.skipSpace()
+	if sel.end, err = c.optionalInt(); err != nil {
+		return nil, err
+	}
+	c.skipSpace()
+	if c.consume(":") {
+		c.skipSpace()
+		step, err := c.optionalInt()
+		if err != nil {
+			return nil, err
+		}
+		if step != nil {
+			sel.step = *step
+		}
+	}
+	return sel, nil
+}
+
+func (c *compiler) optionalInt() (*int, error) {
+	start := c.pos
+	if c.peek() == '-' {
+		c.pos++
+	}
+	for c.pos < len(c.s) && c.s[c.pos] >= '0' && c.s[c.pos] <= '9' {
+		c.pos++
+	}
+	if start == c.pos {
+		return nil, nil
+	}
+	text := c.s[start:c.pos]
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		c.pos = start
+		return nil, c.errorf("invalid integer %q", text)
+	}
+	return &n, nil
+}
+
+func (c *compiler) orExpr() (expr, error) {
+	left, err := c.andExpr()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		c.skipSpace()
+		if !c.consume("||") {
+			return left, nil
+		}
+		right, err := c.andExpr()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left: left, right: right}
+	}
+}
+
+func (c *compiler) andExpr() (expr, error) {
+	left, err := c.unaryExpr()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		c.skipSpace()
+		if !c.consume("&&") {
+			return left, nil
+		}
+		right, err := c.unaryExpr()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left: left, right: right}
+	}
+}
+
+func (c *compiler) unaryExpr() (expr, error) {
+	c.skipSpace()
+	if c.peek() == '!' && !strings.HasPrefix(c.s[c.pos:], "!=") {
+		c.pos++
+		e, err := c.unaryExpr()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{e: e}, nil
+	}
+	if c.consume("(") {
+		e, err := c.orExpr()
+		if err != nil {
+			return nil, err
+		}
+		c.skipSpace()
+		if !c.consume(")") {
+			return nil, c.errorf("expected ')'")
+		}
+		return e, nil
+	}
+	return c.comparison()
+}
+
+var compareOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func (c *compiler) comparison() (expr, error) {
+	left, err := c.operand()
+	if err != nil {
+		return nil, err
+	}
+	c.skipSpace()
+	for _, op := range compareOps {
+		if c.consume(op) {
+			c.skipSpace()
+			right, err := c.operand()
+			if err != nil {
+				return nil, err
+			}
+			return compareExpr{op: op, left: left, right: right}, nil
+		}
+	}
+	q, ok := left.(query)
+	if !ok {
+		return nil, c.errorf("expected comparison operator")
+	}
+	return existsExpr{q: q}, nil
+}
+
+func (c *compiler) operand() (operand, error) {
+	switch b := c.peek(); {
+	case b == '@' || b == '$':
+		c.pos++
+		segments, err := c.segments()
+		if err != nil {
+			return nil, err
+		}
+		return query{relative: b == '@', segments: segments}, nil
+	case b == '\'' || b == '"':
+		s, err := c.stringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return literal{v: parser.NewString(s)}, nil
+	case b == '-' || (b >= '0' && b <= '9'):
+		return c.numberLiteral()
+	case c.consume("true"):
+		return literal{v: parser.Bool(true)}, nil
+	case c.consume("false"):
+		return literal{v: parser.Bool(false)}, nil
+	case c.consume("null"):
+		return literal{v: parser.Null{}}, nil
+	}
+	return nil, c.errorf("expected path or literal")
+}
+
+func (c *compiler) numberLiteral() (operand, error) {
+	start := c.pos
+	for c.pos < len(c.s) && strings.IndexByte("+-.eE0123456789", c.s[c.pos]) >= 0 {
+		c.pos++
+	}
+	text := c.s[start:c.pos]
+	v, err := parser.Parse(strings.NewReader("[" + text + "]"))
+	if err != nil {
+		c.pos = start
+		return nil, c.errorf("invalid number %q", text)
+	}
+	return literal{v: v.(*parser.Array).Elements[0]}, nil
+}
+
+// stringLiteral reads a single- or double-quoted string and returns its
+// decoded text.
+func (c *compiler) stringLiteral() (string, error) {
+	quote := c.s[c.pos]
+	start := c.pos
+	c.pos++
+	var sb strings.Builder
+	for c.pos < len(c.s) {
+		b := c.s[c.pos]
+		if b == quote {
+			c.pos++
+			return sb.String(), nil
+		}
+		if b != '\\' {
+			r, size := utf8.DecodeRuneInString(c.s[c.pos:])
+			sb.WriteRune(r)
+			c.pos += size
+			continue
+		}
+		c.pos++
+		if c.pos >= len(c.s) {
+			break
+		}
+		esc := c.s[c.pos]
+		c.pos++
+		switch esc {
+		case '\'', '"', '\\', '/':
+			sb.WriteByte(esc)
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'u':
+			if c.pos+4 > len(c.s) {
+				return "", c.errorf("short \\u escape")
+			}
+			n, err := strconv.ParseUint(c.s[c.pos:c.pos+4], 16, 32)
+			if err != nil {
+				return "", c.errorf("invalid \\u escape")
+			}
+			sb.WriteRune(rune(n))
+			c.pos += 4
+		default:
+			c.pos--
+			return "", c.errorf("invalid escape character %q", esc)
+		}
+	}
+	c.pos = start
+	return "", c.errorf("unterminated string")
+}
+
+func (c *compiler) peek() byte {
+	if c.pos < len(c.s) {
+		return c.s[c.pos]
+	}
+	return 0
+}
+
+func (c *compiler) consume(prefix string) bool {
+	if strings.HasPrefix(c.s[c.pos:], prefix) {
+		c.pos += len(prefix)
+		return true
+	}
+	return false
+}
+
+func (c *compiler) skipSpace() {
+	for c.pos < len(c.s) && strings.IndexByte(" \t\n\r", c.s[c.pos]) >= 0 {
+		c.pos++
+	}
+}
+
+func (c *compiler) errorf(format string, args ...any) error {
+	return &SyntaxError{Offset: c.pos, Msg: fmt.Sprintf(format, args...)}
+}
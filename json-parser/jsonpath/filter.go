@@ -0,0 +1,130 @@
+package jsonpath
+
+import (
+	"json-parser/parser"
+)
+
+// expr is a filter condition evaluated with @ bound to current.
+type expr interface {
+	test(current, root parser.Value) bool
+}
+
+type orExpr struct {
+	left, right expr
+}
+
+func (e orExpr) test(current, root parser.Value) bool {
+	return e.left.test(current, root) || e.right.test(current, root)
+}
+
+type andExpr struct {
+	left, right expr
+}
+
+func (e andExpr) test(current, root parser.Value) bool {
+	return e.left.test(current, root) && e.right.test(current, root)
+}
+
+type notExpr struct {
+	e expr
+}
+
+func (e notExpr) test(current, root parser.Value) bool {
+	return !e.e.test(current, root)
+}
+
+// existsExpr holds when the query matches at least one value.
+type existsExpr struct {
+	q query
+}
+
+func (e existsExpr) test(current, root parser.Value) bool {
+	return len(e.q.nodes(current, root)) > 0
+}
+
+type compareExpr struct {
+	op          string
+	left, right operand
+}
+
+// operand is one side of a comparison. ok is false when it matches no
+// value, or more than one.
+type operand interface {
+	value(current, root parser.Value) (v parser.Value, ok bool)
+}
+
+type literal struct {
+	v parser.Value
+}
+
+func (l literal) value(_, _ parser.Value) (parser.Value, bool) {
+	return l.v, true
+}
+
+// query is a path inside a filter, starting at @ or at $.
+type query struct {
+	relative bool
+	segments []segment
+}
+
+func (q query) nodes(current, root parser.Value) []parser.Value {
+	start := root
+	if q.relative {
+		start = current
+	}
+	return evaluate(q.segments, []parser.Value{start}, root)
+}
+
+func (q query) value(current, root parser.Value) (parser.Value, bool) {
+	nodes := q.nodes(current, root)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return nodes[0], true
+}
+
+func (e compareExpr) test(current, root parser.Value) bool {
+	a, aok := e.left.value(current, root)
+	b, bok := e.right.value(current, root)
+	switch e.op {
+	case "==":
+		return equal(a, aok, b, bok)
+	case "!=":
+		return !equal(a, aok, b, bok)
+	case "<":
+		return less(a, aok, b, bok)
+	case "<=":
+		return less(a, aok, b, bok) || equal(a, aok, b, bok)
+	case ">":
+		return less(b, bok, a, aok)
+	case ">=":
+		return less(b, bok, a, aok) || equal(a, aok, b, bok)
+	}
+	return false
+}
+
+// equal treats two missing operands as equal to each other and to nothing
+// else.
+func equal(a parser.Value, aok bool, b parser.Value, bok bool) bool {
+	if !aok || !bok {
+		return aok == bok
+	}
+	return parser.Equal(a, b)
+}
+
+// less orders numbers by value and strings by code point; any other pair
+// is unordered.
+func less(a parser.Value, aok bool, b parser.Value, bok bool) bool {
+	if !aok || !bok {
+		return false
+	}
+	switch a := a.(type) {
+	case parser.Number:
+		b, ok := b.(parser.Number)
+		return ok && a.Cmp(b) < 0
+	case parser.String:
+		b, ok := b.(parser.String)
+		return ok && a.Value < b.Value
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+// Package jsonpath evaluates JSONPath expressions such as
+// $.store.book[?(@.price < 10)].title against a parsed document.
+package jsonpath
+
+import (
+	"json-parser/parser"
+)
+
+// Path is a compiled JSONPath expression.
+type Path struct {
+	segments []segment
+}
+
+// segment applies its selectors to the children of every input node, or
+// with descendant set to the node itself and all of its descendants.
+type segment struct {
+	descendant bool
+	selectors  []selector
+}
+
+type selector interface {
+	// selectFrom appends the children of v picked by the selector to out.
+	selectFrom(v parser.Value, root parser.Value, out []parser.Value) []parser.Value
+}
+
+// Evaluate returns the values matched by the path in document order.
+func (p *Path) Evaluate(root parser.Value) []parser.Value {
+	return evaluate(p.segments, []parser.Value{root}, root)
+}
+
+func evaluate(segments []segment, nodes []parser.Value, root parser.Value) []parser.Value {
+	for _, seg := range segments {
+		var next []parser.Value
+		for _, n := range nodes {
+			if seg.descendant {
+				for _, d := range descendants(n, nil) {
+					for _, sel := range seg.selectors {
+						next = sel.selectFrom(d, root, next)
+					}
+				}
+				continue
+			}
+			for _, sel := range seg.selectors {
+				next = sel.selectFrom(n, root, next)
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// descendants appends v and every value nested in it to out, parents
+// before their children.
+func descendants(v parser.Value, out []parser.Value) []parser.Value {
+	out = append(out, v)
+	for _, c := range children(v) {
+		out = descendants(c, out)
+	}
+	return out
+}
+
+func children(v parser.Value) []parser.Value {
+	switch v := v.(type) {
+	case *parser.Object:
+		values := make([]parser.Value, 0, len(v.Members))
+		for _, m := range v.Members {
+			values = append(values, m.Value)
+		}
+		return values
+	case *parser.Array:
+		return v.Elements
+	}
+	return nil
+}
+
+type nameSelector string
+
+func (s nameSelector) selectFrom(v parser.Value, _ parser.Value, out []parser.Value) []parser.Value {
+	if obj, ok := v.(*parser.Object); ok {
+		if c, ok := obj.Get(string(s)); ok {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+type wildcardSelector struct{}
+
+func (wildcardSelector) selectFrom(v parser.Value, _ parser.Value, out []parser.Value) []parser.Value {
+	return append(out, children(v)...)
+}
+
+type indexSelector int
+
+func (s indexSelector) selectFrom(v parser.Value, _ parser.Value, out []parser.Value) []parser.Value {
+	arr, ok := v.(*parser.Array)
+	if !ok {
+		return out
+	}
+	i := int(s)
+	if i < 0 {
+		i += len(arr.Elements)
+	}
+	if i >= 0 && i < len(arr.Elements) {
+		out = append(out, arr.Elements[i])
+	}
+	return out
+}
+
+// sliceSelector is [start:end:step]; a nil bound takes its default.
+type sliceSelector struct {
+	start, end *int
+	step       int
+}
+
+func (s sliceSelector) selectFrom(v parser.Value, _ parser.Value, out []parser.Value) []parser.Value {
+	arr, ok := v.(*parser.Array)
+	if !ok || s.step == 0 {
+		return out
+	}
+	n := len(arr.Elements)
+	normalize := func(i int) int {
+		if i < 0 {
+			return i + n
+		}
+		return i
+	}
+	if s.step > 0 {
+		lower, upper := 0, n
+		if s.start != nil {
+			lower = min(max(normalize(*s.start), 0), n)
+		}
+		if s.end != nil {
+			upper = min(max(normalize(*s.end), 0), n)
+		}
+		for i := lower; i < upper; i += s.step {
+			out = append(out, arr.Elements[i])
+			// Stop before a huge step could overflow i.
+			if s.step >= upper-i {
+				break
+			}
+		}
+		return out
+	}
+	upper, lower := n-1, -1
+	if s.start != nil {
+		upper = min(max(normalize(*s.start), -1), n-1)
+	}
+	if s.end != nil {
+		lower = min(max(normalize(*s.end), -1), n-1)
+	}
+	for i := upper; i > lower; i += s.step {
+		out = append(out, arr.Elements[i])
+		if s.step <= lower-i {
+			break
+		}
+	}
+	return out
+}
+
+// filterSelector keeps the children for which the expression holds.
+type filterSelector struct {
+	cond expr
+}
+
+func (s filterSelector) selectFrom(v parser.Value, root parser.Value, out []parser.Value) []parser.Value {
+	for _, c := range children(v) {
+		if s.cond.test(c, root) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
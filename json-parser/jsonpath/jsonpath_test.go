@@ -0,0 +1,100 @@
+package jsonpath
+
+import (
+	"json-parser/parser"
+	"strings"
+	"testing"
+)
+
+const store = `{"store": {
+	"book": [
+		{"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+		{"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+		{"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+		{"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+	],
+	"bicycle": {"color": "red", "price": 19.95}
+}}`
+
+func TestEvaluate(t *testing.T) {
+	doc, err := parser.Parse(strings.NewReader(store))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"root", "$", ""},
+		{"child", "$.store.bicycle.color", `["red"]`},
+		{"bracket child", `$['store']["bicycle"]['color']`, `["red"]`},
+		{"wildcard", "$.store.bicycle.*", `["red",19.95]`},
+		{"index", "$.store.book[1].author", `["Evelyn Waugh"]`},
+		{"negative index", "$.store.book[-1].author", `["J. R. R. Tolkien"]`},
+		{"out of range index", "$.store.book[9]", `[]`},
+		{"union", "$.store.book[0,2].price", `[8.95,8.99]`},
+		{"slice", "$.store.book[1:3].price", `[12.99,8.99]`},
+		{"reverse slice", "$.store.book[::-1].price", `[22.99,8.99,12.99,8.95]`},
+		{"slice with huge step", "$.store.book[1::9223372036854775807].price", `[12.99]`},
+		{"reverse slice with huge step", "$.store.book[::-9223372036854775808].price", `[22.99]`},
+		{"descendant", "$..price", `[8.95,12.99,8.99,22.99,19.95]`},
+		{"descendant wildcard", "$.store.bicycle..*", `["red",19.95]`},
+		{"filter less than", "$.store.book[?(@.price < 10)].title", `["Sayings of the Century","Moby Dick"]`},
+		{"filter without parentheses", "$.store.book[?@.price >= 22.99].title", `["The Lord of the Rings"]`},
+		{"filter string equality", `$..book[?(@.category == 'reference')].author`, `["Nigel Rees"]`},
+		{"filter existence", "$..book[?(@.isbn)].title", `["Moby Dick","The Lord of the Rings"]`},
+		{"filter negation", "$..book[?(!@.isbn)].price", `[8.95,12.99]`},
+		{"filter and or", "$..book[?(@.isbn && @.price > 10 || @.price < 9)].price", `[8.95,8.99,22.99]`},
+		{"filter absolute path", "$..book[?(@.price > $.store.bicycle.price)].price", `[22.99]`},
+		{"filter numeric equality", "$..book[?(@.price == 8.950)].title", `["Sayings of the Century"]`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := Compile(tc.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			matches := p.Evaluate(doc)
+			if tc.want == "" {
+				if len(matches) != 1 || matches[0] != doc {
+					t.Fatalf("expected the root document, got %v", matches)
+				}
+				return
+			}
+			actual := string(parser.Marshal(&parser.Array{Elements: matches}))
+			if actual != tc.want {
+				t.Fatalf("unexpected output.\nexpected: %s\nactual: %s", tc.want, actual)
+			}
+		})
+	}
+}
+
+func TestCompileInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		offset int
+	}{
+		{"missing root", "store.book", 0},
+		{"unclosed bracket", "$.store[0", 9},
+		{"empty name", "$.", 2},
+		{"unterminated string", "$['store", 2},
+		{"literal without comparison", "$[?(1)]", 5},
+		{"trailing data", "$.a b", 4},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Compile(tc.path)
+			syntaxErr, ok := err.(*SyntaxError)
+			if !ok {
+				t.Fatalf("expected *SyntaxError, got %v", err)
+			}
+			if syntaxErr.Offset != tc.offset {
+				t.Fatalf("unexpected offset.\nexpected: %d\nactual: %d (%v)", tc.offset, syntaxErr.Offset, err)
+			}
+		})
+	}
+}
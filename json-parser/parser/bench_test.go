@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// generateDocument returns an array of records that mixes every kind of
+// value, nesting and escapes. The output is the same for the same records.
+func generateDocument(records int) []byte {
+	var sb strings.Builder
+	sb.WriteString("[\n")
+	for i := range records {
+		if i > 0 {
+			sb.WriteString(",\n")
+		}
+		fmt.Fprintf(&sb, `  {"id": %d, "name": "user \"%d\" é世", "score": %d.%03de-2, "active": %t, "manager": null,`,
+			i, i, i*7919%100000, i%1000, i%3 == 0)
+		fmt.Fprintf(&sb, ` "tags": ["t%d", "line\nbreak", "tab\t"], "address": {"street": "%d Main St", "geo": [%d.5, -%d.25]}}`,
+			i%10, i, i%90, i%180)
+	}
+	sb.WriteString("\n]\n")
+	return []byte(sb.String())
+}
+
+var benchSizes = []struct {
+	name    string
+	records int
+}{
+	{"1k", 1_000},
+	{"50k", 50_000},
+}
+
+func BenchmarkParse(b *testing.B) {
+	for _, size := range benchSizes {
+		data := generateDocument(size.records)
+		b.Run(size.name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := Parse(bytes.NewReader(data)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecoder(b *testing.B) {
+	for _, size := range benchSizes {
+		data := generateDocument(size.records)
+		b.Run(size.name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for b.Loop() {
+				d := NewDecoder(bytes.NewReader(data))
+				for {
+					_, err := d.Token()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFormat(b *testing.B) {
+	data := generateDocument(benchSizes[len(benchSizes)-1].records)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := Format(io.Discard, NewDecoder(bytes.NewReader(data)), "  "); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkEncodingJSONValid is a baseline for comparing throughput.
+func BenchmarkEncodingJSONValid(b *testing.B) {
+	data := generateDocument(benchSizes[len(benchSizes)-1].records)
+	b.SetBytes(int64(len(data)))
+	for b.Loop() {
+		if !json.Valid(data) {
+			b.Fatal("generated document is invalid")
+		}
+	}
+}
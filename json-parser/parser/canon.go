@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Canonicalize returns v in the JSON Canonicalization Scheme of RFC 8785:
+// no whitespace, object members sorted by their UTF-16 code units, numbers
+// written the way ECMAScript prints doubles and strings with the minimal
+// escaping. It fails for objects with duplicate keys and for numbers that
+// are not finite doubles.
+func Canonicalize(v Value) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := canonicalize(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func canonicalize(buf *bytes.Buffer, v Value) error {
+	switch v := v.(type) {
+	case *Object:
+		members := slices.Clone(v.Members)
+		slices.SortStableFunc(members, func(a, b Member) int {
+			return slices.Compare(utf16.Encode([]rune(a.Key.Value)), utf16.Encode([]rune(b.Key.Value)))
+		})
+		buf.WriteByte('{')
+		for i, m := range members {
+			if i > 0 {
+				if members[i-1].Key.Value == m.Key.Value {
+					return fmt.Errorf("duplicate key %q cannot be canonicalized", m.Key.Value)
+				}
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('"')
+			buf.WriteString(escapeString(m.Key.Value))
+			buf.WriteString(`":`)
+			if err := canonicalize(buf, m.Value); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case *Array:
+		buf.WriteByte('[')
+		for i, e := range v.Elements {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := canonicalize(buf, e); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case String:
+		buf.WriteByte('"')
+		buf.WriteString(escapeString(v.Value))
+		buf.WriteByte('"')
+	case Number:
+		f, err := v.Float64()
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return fmt.Errorf("number %s is not representable as a finite double", v.Literal)
+		}
+		buf.WriteString(formatES6(f))
+	case Bool:
+		buf.WriteString(strconv.FormatBool(bool(v)))
+	case Null:
+		buf.WriteString("null")
+	}
+	return nil
+}
+
+// formatES6 formats f like ECMAScript's Number.prototype.toString, using
+// the shortest digits that round-trip.
+func formatES6(f float64) string {
+	if f == 0 {
+		return "0"
+	}
+	sign := ""
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+	// Split the shortest representation d.ddde±x into its digits and the
+	// position n of the decimal point relative to them.
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exp, _ := strings.Cut(s, "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	e, _ := strconv.Atoi(exp)
+	k, n := len(digits), e+1
+
+	switch {
+	case k <= n && n <= 21:
+		return sign + digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= 21:
+		return sign + digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0:
+		return sign + "0." + strings.Repeat("0", -n) + digits
+	}
+	expSign := "+"
+	if n-1 < 0 {
+		expSign = "-"
+	}
+	exponent := expSign + strconv.Itoa(max(n-1, 1-n))
+	if k == 1 {
+		return sign + digits + "e" + exponent
+	}
+	return sign + digits[:1] + "." + digits[1:] + "e" + exponent
+}
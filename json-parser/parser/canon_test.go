@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"sorted keys", `{"b": 1, "a": {"d": [], "c": null}}`, `{"a":{"c":null,"d":[]},"b":1}`},
+		// Sorting is by UTF-16 code units, so U+10000 (a surrogate pair)
+		// sorts before U+FB33.
+		{"utf-16 order", `{"\ufb33": 1, "\ud800\udc00": 2, "\u20ac": 3, "\r": 4, "1": 5}`, "{\"\\r\":4,\"1\":5,\"€\":3,\"\U00010000\":2,\"\uFB33\":1}"},
+		{"minimal escaping", `["\u0041\/\u00e9\u001F\n\"\\"]`, `["A/é\u001f\n\"\\"]`},
+		{"numbers", `[1.0, -0, 1e2, 1E-7, 0.000001, 1e23, 4.50, 2e-3, 1e21, 1e20, -1.5e300]`,
+			`[1,0,100,1e-7,0.000001,1e+23,4.5,0.002,1e+21,100000000000000000000,-1.5e+300]`},
+		{"literals", `[true, false, null]`, `[true,false,null]`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := Parse(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual, err := Canonicalize(v)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(actual) != tc.want {
+				t.Fatalf("unexpected output.\nexpected: %s\nactual: %s", tc.want, actual)
+			}
+		})
+	}
+
+	for _, input := range []string{`{"a": 1, "a": 2}`, `[1e400]`} {
+		v, err := Parse(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := Canonicalize(v); err == nil {
+			t.Fatalf("expected an error for %s", input)
+		}
+	}
+}
+
+func TestFormatES6(t *testing.T) {
+	// Values from the RFC 8785 number serialization samples.
+	tests := []struct {
+		f    float64
+		want string
+	}{
+		{math.Float64frombits(0x0000000000000001), "5e-324"},
+		{math.Float64frombits(0x7fefffffffffffff), "1.7976931348623157e+308"},
+		{math.Float64frombits(0x4340000000000000), "9007199254740992"},
+		{math.Float64frombits(0x444b1ae4d6e2ef50), "1e+21"},
+		{math.Float64frombits(0x3eb0c6f7a0b5ed8d), "0.000001"},
+		{math.Float64frombits(0x3eb0c6f7a0b5ed8c), "9.999999999999997e-7"},
+		{math.Float64frombits(0x41b3de4355555553), "333333333.3333332"},
+		{math.Float64frombits(0xc43211ede4974a35), "-333333333333333300000"},
+	}
+
+	for _, tc := range tests {
+		if actual := formatES6(tc.f); actual != tc.want {
+			t.Fatalf("formatES6(%v) = %s, expected %s", tc.f, actual, tc.want)
+		}
+	}
+}
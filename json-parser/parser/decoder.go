@@ -0,0 +1,452 @@
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TokenKind identifies what a Token returned by Decoder.Token stands for.
+type TokenKind int
+
+const (
+	ObjectStart TokenKind = iota
+	ObjectEnd
+	ArrayStart
+	ArrayEnd
+	// ObjectKey is a member name; its Value is a String.
+	ObjectKey
+	// ScalarValue is a string, number, boolean or null.
+	ScalarValue
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case ObjectStart:
+		return "object start"
+	case ObjectEnd:
+		return "object end"
+	case ArrayStart:
+		return "array start"
+	case ArrayEnd:
+		return "array end"
+	case ObjectKey:
+		return "object key"
+	case ScalarValue:
+		return "scalar value"
+	}
+	return "unknown"
+}
+
+// Token is a single step through a document. Value is only set for
+// ObjectKey and ScalarValue tokens.
+type Token struct {
+	Kind  TokenKind
+	Value Value
+	// Pos is where the token starts in the input and End is just past it.
+	Pos Position
+	End Position
+}
+
+// scanState is what a container expects to read next.
+type scanState int
+
+const (
+	// stateKeyOrEnd follows '{': a key or '}'
+	stateKeyOrEnd scanState = iota
+	// stateKey follows ',' in an object
+	stateKey
+	// stateColon follows a key
+	stateColon
+	// stateValueOrEnd follows '[': a value or ']'
+	stateValueOrEnd
+	// stateValue follows ':' in an object or ',' in an array
+	stateValue
+	// stateCommaOrEnd follows a complete member or element
+	stateCommaOrEnd
+)
+
+type frame struct {
+	kind  Kind
+	state scanState
+	// keys records where each key of an object was first seen. It is only
+	// kept when duplicate keys are checked.
+	keys map[string]Position
+}
+
+// Decoder reads a JSON document token by token, holding only the current
+// token and the chain of open containers in memory.
+type Decoder struct {
+	p       *parser
+	opts    Options
+	stack   []frame
+	started bool
+	err     error
+	// errs collects the errors skipped over in recovery mode.
+	errs ErrorList
+}
+
+// NewDecoder returns a Decoder reading from r with the default Options.
+func NewDecoder(r io.Reader) *Decoder {
+	return NewDecoderWithOptions(r, Options{})
+}
+
+// NewDecoderWithOptions returns a Decoder reading from r that checks the
+// document according to opts.
+func NewDecoderWithOptions(r io.Reader, opts Options) *Decoder {
+	reader, ok := r.(*bufio.Reader)
+	if !ok {
+		reader = bufio.NewReader(r)
+	}
+	return &Decoder{p: &parser{reader: reader, pos: Position{Line: 1, Column: 1}, opts: opts}, opts: opts}
+}
+
+// Depth returns the number of containers that are currently open.
+func (d *Decoder) Depth() int {
+	return len(d.stack)
+}
+
+// Token returns the next token of the document. Once the top-level value is
+// complete and only whitespace is left, it returns io.EOF. Any error is
+// sticky and returned again by later calls.
+//
+// With Options.Recover, syntax errors inside containers are collected
+// instead and the tokens after them are still returned. The errors are
+// returned as an ErrorList in place of io.EOF or the next fatal error.
+func (d *Decoder) Token() (Token, error) {
+	if d.err != nil {
+		return Token{}, d.err
+	}
+	for {
+		tok, err := d.next()
+		if err == nil {
+			tok.End = d.p.pos
+			return tok, nil
+		}
+		var syntaxErr *SyntaxError
+		if d.opts.Recover && len(d.stack) > 0 && errors.As(err, &syntaxErr) {
+			d.errs = append(d.errs, err)
+			if max := d.opts.MaxErrors; max == 0 || len(d.errs) < max {
+				tok, closed, err := d.resync(syntaxErr)
+				if err == nil {
+					if closed {
+						tok.End = d.p.pos
+						return tok, nil
+					}
+					continue
+				}
+				// Running out of input is only worth a second error if
+				// the first one was about something else.
+				if syntaxErr.Found != endOfInput {
+					d.errs = append(d.errs, err)
+				}
+			}
+			d.err = d.errs
+			return Token{}, d.err
+		}
+		if len(d.errs) > 0 {
+			if err != io.EOF {
+				d.errs = append(d.errs, err)
+			}
+			err = d.errs
+		}
+		d.err = err
+		return Token{}, err
+	}
+}
+
+// resync skips input after the syntax error cause up to the next ',' or the
+// bracket closing the innermost container. For ',' it leaves the container
+// expecting the next member or element, and for the bracket it returns the
+// closing token with closed set.
+func (d *Decoder) resync(cause *SyntaxError) (tok Token, closed bool, err error) {
+	p := d.p
+	// The rune that caused the error may itself be where to resume.
+	if cause.Found != endOfInput && cause.Position == p.last {
+		p.unreadRune()
+	}
+	top := &d.stack[len(d.stack)-1]
+	end := '}'
+	if top.kind == ArrayKind {
+		end = ']'
+	}
+	for {
+		r, err := p.readRune()
+		if err != nil {
+			return Token{}, false, p.unexpected(err, describeRune(end))
+		}
+		switch r {
+		case ',':
+			top.state = stateValue
+			if top.kind == ObjectKind {
+				top.state = stateKey
+			}
+			return Token{}, false, nil
+		case end:
+			return d.closeContainer(), true, nil
+		}
+	}
+}
+
+func (d *Decoder) next() (Token, error) {
+	p := d.p
+	for {
+		if err := p.skipWhitespace(); err != nil {
+			return Token{}, err
+		}
+		if len(d.stack) == 0 {
+			return d.nextTopLevel()
+		}
+		top := &d.stack[len(d.stack)-1]
+		switch top.state {
+		case stateValue:
+			if top.kind == ArrayKind && d.opts.Dialect != DialectJSON {
+				// A trailing comma may be followed by the closing bracket
+				if r, err := p.readRune(); err == nil {
+					if r == ']' {
+						return d.closeContainer(), nil
+					}
+					p.unreadRune()
+				}
+			}
+			top.state = stateCommaOrEnd
+			return d.nextValue()
+		case stateValueOrEnd:
+			r, err := p.readRune()
+			if err != nil {
+				return Token{}, p.unexpected(err, "value", describeRune(']'))
+			}
+			if r == ']' {
+				return d.closeContainer(), nil
+			}
+			p.unreadRune()
+			top.state = stateCommaOrEnd
+			return d.nextValue()
+		case stateKeyOrEnd, stateKey:
+			/*
+				Rules for a json object:
+				1. JSON object must start with { and end with }
+				2. JSON object must have a key value pair, and a comma after each key value pair
+				3. Key must be enclosed in double quotes
+			*/
+			// A trailing comma may be followed by the closing brace
+			endAllowed := top.state == stateKeyOrEnd || d.opts.Dialect != DialectJSON
+			nextExpectedDelims := []rune{'"'}
+			if endAllowed {
+				nextExpectedDelims = []rune{'}', '"'}
+			}
+			r, err := p.readRune()
+			if err != nil {
+				return Token{}, p.unexpected(err, describeRunes(nextExpectedDelims)...)
+			}
+			if r == '}' && endAllowed {
+				return d.closeContainer(), nil
+			}
+			pos := p.last
+			var key String
+			switch {
+			case r == '"':
+				key, err = p.parseString('"')
+			case r == '\'' && d.opts.Dialect == DialectJSON5:
+				key, err = p.parseString('\'')
+			case isIdentifierStart(r) && d.opts.Dialect == DialectJSON5:
+				p.unreadRune()
+				key, err = p.parseIdentifier()
+			default:
+				return Token{}, p.unexpectedRune(r, describeRunes(nextExpectedDelims)...)
+			}
+			if err != nil {
+				return Token{}, err
+			}
+			if err := d.checkDuplicateKey(top, key.Value, pos); err != nil {
+				return Token{}, err
+			}
+			top.state = stateColon
+			return Token{Kind: ObjectKey, Value: key, Pos: pos}, nil
+		case stateColon:
+			r, err := p.readRune()
+			if err != nil {
+				return Token{}, p.unexpected(err, describeRune(':'))
+			}
+			if r != ':' {
+				return Token{}, p.unexpectedRune(r, describeRune(':'))
+			}
+			top.state = stateValue
+		case stateCommaOrEnd:
+			nextExpectedDelims := []rune{']', ','}
+			if top.kind == ObjectKind {
+				nextExpectedDelims = []rune{',', '}'}
+			}
+			r, err := p.readRune()
+			if err != nil {
+				return Token{}, p.unexpected(err, describeRunes(nextExpectedDelims)...)
+			}
+			switch {
+			case r == ',' && top.kind == ObjectKind:
+				top.state = stateKey
+			case r == ',':
+				top.state = stateValue
+			case r == '}' && top.kind == ObjectKind, r == ']' && top.kind == ArrayKind:
+				return d.closeContainer(), nil
+			default:
+				return Token{}, p.unexpectedRune(r, describeRunes(nextExpectedDelims)...)
+			}
+		}
+	}
+}
+
+// nextTopLevel handles the start of the document and what follows the
+// top-level value.
+func (d *Decoder) nextTopLevel() (Token, error) {
+	p := d.p
+	r, err := p.readRune()
+	if d.started {
+		if err != nil {
+			return Token{}, err
+		}
+		// Any non-whitespace after a complete value is invalid JSON
+		return Token{}, p.syntaxError("unexpected data after top-level value", describeRune(r))
+	}
+	if d.opts.RFC == RFC8259 {
+		// Any value is allowed at the top level
+		if err == nil {
+			p.unreadRune()
+		}
+		d.started = true
+		return d.nextValue()
+	}
+	if err != nil {
+		return Token{}, p.unexpected(err, describeRune('{'), describeRune('['))
+	}
+	if r != '{' && r != '[' {
+		// Top-level must be object or array
+		return Token{}, p.syntaxError("top-level value must be an object or an array", describeRune(r))
+	}
+	d.started = true
+	return d.openContainer(r)
+}
+
+// nextValue reads a value where one is required. The caller has already
+// moved the enclosing container on to its next state.
+func (d *Decoder) nextValue() (Token, error) {
+	/*
+		Rules for a json value:
+			1. JSON value can be a string, number, boolean, null, object, array
+			2. JSON value can be enclosed in double quotes
+	*/
+	p := d.p
+	if err := p.skipWhitespace(); err != nil {
+		return Token{}, err
+	}
+	r, err := p.readRune()
+	if err != nil {
+		return Token{}, p.unexpected(err, "value")
+	}
+	pos := p.last
+	var v Value
+	switch {
+	case r == '{' || r == '[':
+		return d.openContainer(r)
+	case r == '"':
+		v, err = p.parseString('"')
+	case r == '\'' && d.opts.Dialect == DialectJSON5:
+		v, err = p.parseString('\'')
+	case strings.ContainsRune("+-IN", r) && d.opts.Dialect == DialectJSON5,
+		isDigit(r) && d.opts.Dialect == DialectJSON5:
+		p.unreadRune()
+		v, err = p.parseJSON5Number()
+	case r == 'n':
+		v, err = Null{}, p.parseSequence("ull")
+	case r == 't':
+		v, err = Bool(true), p.parseSequence("rue")
+	case r == 'f':
+		v, err = Bool(false), p.parseSequence("alse")
+	case isDigit(r) || r == '-':
+		p.unreadRune()
+		v, err = p.parseNumber()
+	default:
+		return Token{}, p.unexpectedRune(r, "value")
+	}
+	if err != nil {
+		return Token{}, err
+	}
+	if n, ok := v.(Number); ok {
+		if err := d.checkNumber(n, pos); err != nil {
+			return Token{}, err
+		}
+	}
+	return Token{Kind: ScalarValue, Value: v, Pos: pos}, nil
+}
+
+// checkDuplicateKey applies the duplicate key policy to a key read at pos
+// in the object f.
+func (d *Decoder) checkDuplicateKey(f *frame, key string, pos Position) error {
+	if d.opts.DuplicateKeys == DuplicateKeysAllow {
+		return nil
+	}
+	if f.keys == nil {
+		f.keys = make(map[string]Position)
+	}
+	first, seen := f.keys[key]
+	if !seen {
+		f.keys[key] = pos
+		return nil
+	}
+	err := &SyntaxError{
+		Position: pos,
+		Reason:   fmt.Sprintf("duplicate key %q, first defined at line %d, column %d", key, first.Line, first.Column),
+	}
+	if d.opts.DuplicateKeys == DuplicateKeysError {
+		return err
+	}
+	if d.opts.Warn != nil {
+		d.opts.Warn(err)
+	}
+	return nil
+}
+
+// checkNumber applies Options.Numbers and Options.WarnUnsafeIntegers to a
+// number read at pos.
+func (d *Decoder) checkNumber(n Number, pos Position) error {
+	var err error
+	switch d.opts.Numbers {
+	case NumberFloat64:
+		_, err = n.Float64()
+	case NumberInt64:
+		_, err = n.Int64()
+	}
+	if err != nil {
+		return &SyntaxError{Position: pos, Reason: fmt.Sprintf("number %s cannot be represented as %s", n.Literal, d.opts.Numbers)}
+	}
+	if d.opts.WarnUnsafeIntegers && d.opts.Warn != nil && n.isUnsafeInteger() {
+		d.opts.Warn(&SyntaxError{Position: pos, Reason: fmt.Sprintf("integer %s is beyond 2^53 and loses precision as a double", n.Literal)})
+	}
+	return nil
+}
+
+// openContainer pushes the object or array started by r, which has just
+// been read.
+func (d *Decoder) openContainer(r rune) (Token, error) {
+	if max := d.opts.MaxDepth; max > 0 && len(d.stack) >= max {
+		return Token{}, &LimitError{Position: d.p.last, Limit: "nesting depth", Max: int64(max)}
+	}
+	if r == '{' {
+		d.stack = append(d.stack, frame{kind: ObjectKind, state: stateKeyOrEnd})
+		return Token{Kind: ObjectStart, Pos: d.p.last}, nil
+	}
+	d.stack = append(d.stack, frame{kind: ArrayKind, state: stateValueOrEnd})
+	return Token{Kind: ArrayStart, Pos: d.p.last}, nil
+}
+
+// closeContainer pops the innermost container whose closing bracket has
+// just been read.
+func (d *Decoder) closeContainer() Token {
+	top := d.stack[len(d.stack)-1]
+	d.stack = d.stack[:len(d.stack)-1]
+	if top.kind == ObjectKind {
+		return Token{Kind: ObjectEnd, Pos: d.p.last}
+	}
+	return Token{Kind: ArrayEnd, Pos: d.p.last}
+}
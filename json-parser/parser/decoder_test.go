@@ -0,0 +1,187 @@
+package parser
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDecoderToken(t *testing.T) {
+	input := `{"a": [1, "x"], "b": {}, "c": null}`
+	// The input is a single line, so columns follow from offsets.
+	at := func(offset int64) Position {
+		return Position{Offset: offset, Line: 1, Column: int(offset) + 1}
+	}
+	want := []Token{
+		{Kind: ObjectStart, Pos: at(0), End: at(1)},
+		{Kind: ObjectKey, Value: String{Value: "a", Raw: "a"}, Pos: at(1), End: at(4)},
+		{Kind: ArrayStart, Pos: at(6), End: at(7)},
+		{Kind: ScalarValue, Value: Number{Literal: "1"}, Pos: at(7), End: at(8)},
+		{Kind: ScalarValue, Value: String{Value: "x", Raw: "x"}, Pos: at(10), End: at(13)},
+		{Kind: ArrayEnd, Pos: at(13), End: at(14)},
+		{Kind: ObjectKey, Value: String{Value: "b", Raw: "b"}, Pos: at(16), End: at(19)},
+		{Kind: ObjectStart, Pos: at(21), End: at(22)},
+		{Kind: ObjectEnd, Pos: at(22), End: at(23)},
+		{Kind: ObjectKey, Value: String{Value: "c", Raw: "c"}, Pos: at(25), End: at(28)},
+		{Kind: ScalarValue, Value: Null{}, Pos: at(30), End: at(34)},
+		{Kind: ObjectEnd, Pos: at(34), End: at(35)},
+	}
+
+	d := NewDecoder(strings.NewReader(input))
+	var actual []Token
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		actual = append(actual, tok)
+	}
+	if !reflect.DeepEqual(actual, want) {
+		t.Fatalf("unexpected tokens.\nexpected: %v\nactual: %v", want, actual)
+	}
+}
+
+func TestDecoderStickyError(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`[1 2]`))
+	for i := 0; i < 2; i++ {
+		if _, err := d.Token(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	_, err := d.Token()
+	if !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid, got %v", err)
+	}
+	if _, again := d.Token(); again != err {
+		t.Fatalf("expected the same error again, got %v", again)
+	}
+}
+
+func TestParseDeepNesting(t *testing.T) {
+	depth := 1000000
+	input := strings.Repeat("[", depth) + strings.Repeat("]", depth)
+	v, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := v.(*Array); !ok {
+		t.Fatalf("expected an array, got %#v", v)
+	}
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	input := "{\"a\": 1,\n \"b\": {\"a\": 2},\n \"a\": 3}"
+	reason := `duplicate key "a", first defined at line 1, column 2`
+
+	if _, err := Parse(strings.NewReader(input)); err != nil {
+		t.Fatalf("expected duplicates to be allowed by default, got %v", err)
+	}
+
+	var warnings []error
+	v, err := ParseWithOptions(strings.NewReader(input), Options{
+		DuplicateKeys: DuplicateKeysWarn,
+		Warn:          func(err error) { warnings = append(warnings, err) },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].(*SyntaxError).Reason != reason {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+	if got, _ := v.(*Object).Get("a"); got != (Number{Literal: "3"}) {
+		t.Fatalf("expected the last duplicate to win, got %#v", got)
+	}
+
+	_, err = ParseWithOptions(strings.NewReader(input), Options{DuplicateKeys: DuplicateKeysError})
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("expected *SyntaxError, got %v", err)
+	}
+	if syntaxErr.Position != (Position{Offset: 26, Line: 3, Column: 2}) || syntaxErr.Reason != reason {
+		t.Fatalf("unexpected error: %v", syntaxErr)
+	}
+}
+
+func TestDecoderRecover(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		maxErrors int
+		want      []string
+	}{
+		{
+			name:  "several errors",
+			input: `{"a": tru, "b": [1, 2 3, x], "c": {"d" 1}, "e": 01}`,
+			want:  []string{"1:10", "1:23", "1:26", "1:40", "1:50"},
+		},
+		{
+			name:  "strings and literals",
+			input: "[\n  \"a\\q\",\n  nul,\n  3\n]",
+			want:  []string{"2:6", "3:6"},
+		},
+		{
+			name:  "unclosed array",
+			input: `{"a": [1, }`,
+			want:  []string{"1:11", "1:12"},
+		},
+		{
+			name:      "maximum",
+			input:     `[x, y, z]`,
+			maxErrors: 2,
+			want:      []string{"1:2", "1:5"},
+		},
+		{
+			name:  "top level",
+			input: `[x] y`,
+			want:  []string{"1:2", "1:5"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseWithOptions(strings.NewReader(tc.input), Options{Recover: true, MaxErrors: tc.maxErrors})
+			var list ErrorList
+			if !errors.As(err, &list) || !errors.Is(err, ErrInvalid) {
+				t.Fatalf("expected an ErrorList, got %v", err)
+			}
+			var actual []string
+			for _, e := range list {
+				var syntaxErr *SyntaxError
+				if !errors.As(e, &syntaxErr) {
+					t.Fatalf("expected *SyntaxError, got %v", e)
+				}
+				actual = append(actual, syntaxErr.Position.String())
+			}
+			if !slices.Equal(actual, tc.want) {
+				t.Fatalf("unexpected error positions.\nexpected: %q\nactual: %q\n%v", tc.want, actual, err)
+			}
+		})
+	}
+
+	// Tokens after an error are still returned.
+	d := NewDecoderWithOptions(strings.NewReader(`[1, x, 3]`), Options{Recover: true})
+	var kinds []TokenKind
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if _, ok := err.(ErrorList); !ok {
+				t.Fatalf("expected an ErrorList, got %v", err)
+			}
+			break
+		}
+		kinds = append(kinds, tok.Kind)
+	}
+	if want := []TokenKind{ArrayStart, ScalarValue, ScalarValue, ArrayEnd}; !slices.Equal(kinds, want) {
+		t.Fatalf("unexpected tokens.\nexpected: %v\nactual: %v", want, kinds)
+	}
+
+	if _, err := ParseWithOptions(strings.NewReader(`{"a": [1, 2]}`), Options{Recover: true}); err != nil {
+		t.Fatalf("unexpected error for a valid document: %v", err)
+	}
+}
@@ -0,0 +1,288 @@
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// ErrEncoderState is wrapped by the errors an Encoder returns when a call
+// would make the output malformed, such as a Key inside an array.
+var ErrEncoderState = errors.New("invalid encoder call")
+
+// Encoder writes a JSON document to an io.Writer one token at a time, so
+// large documents never have to be held in memory. Every call is checked
+// against the structure written so far; a call that would produce
+// malformed JSON writes nothing and returns an error wrapping
+// ErrEncoderState. Strings are always escaped from their decoded Value.
+type Encoder struct {
+	f     *formatter
+	stack []encoderFrame
+	// done is set once the top-level value is complete.
+	done bool
+}
+
+type encoderFrame struct {
+	kind Kind
+	// needKey is set while an object expects a key or its end.
+	needKey bool
+}
+
+// NewEncoder returns an Encoder writing minified JSON to w. Output is
+// buffered, so Close or Flush must be called at the end.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{f: &formatter{w: bufio.NewWriter(w)}}
+}
+
+// SetIndent puts every member and element on its own line, indented by
+// indent per level of nesting, as Format does.
+func (e *Encoder) SetIndent(indent string) {
+	e.f.indent = indent
+}
+
+// BeginObject starts an object, which is ended by EndObject.
+func (e *Encoder) BeginObject() error {
+	return e.write(Token{Kind: ObjectStart})
+}
+
+// EndObject ends the innermost object.
+func (e *Encoder) EndObject() error {
+	return e.write(Token{Kind: ObjectEnd})
+}
+
+// BeginArray starts an array, which is ended by EndArray.
+func (e *Encoder) BeginArray() error {
+	return e.write(Token{Kind: ArrayStart})
+}
+
+// EndArray ends the innermost array.
+func (e *Encoder) EndArray() error {
+	return e.write(Token{Kind: ArrayEnd})
+}
+
+// Key writes the name of the next member of the innermost object.
+func (e *Encoder) Key(name string) error {
+	return e.write(Token{Kind: ObjectKey, Value: NewString(name)})
+}
+
+// String writes a string value.
+func (e *Encoder) String(s string) error {
+	return e.write(Token{Kind: ScalarValue, Value: NewString(s)})
+}
+
+// Int writes an integer value.
+func (e *Encoder) Int(i int64) error {
+	return e.write(Token{Kind: ScalarValue, Value: Number{Literal: strconv.FormatInt(i, 10)}})
+}
+
+// Float writes a number value using the fewest digits that round-trip.
+// NaN and infinities have no JSON form and are rejected.
+func (e *Encoder) Float(f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("%w: %v is not a JSON number", ErrEncoderState, f)
+	}
+	return e.write(Token{Kind: ScalarValue, Value: Number{Literal: strconv.FormatFloat(f, 'g', -1, 64)}})
+}
+
+// Number writes n, whose Literal must be a valid JSON number.
+func (e *Encoder) Number(n Number) error {
+	return e.write(Token{Kind: ScalarValue, Value: n})
+}
+
+// Bool writes true or false.
+func (e *Encoder) Bool(b bool) error {
+	return e.write(Token{Kind: ScalarValue, Value: Bool(b)})
+}
+
+// Null writes null.
+func (e *Encoder) Null() error {
+	return e.write(Token{Kind: ScalarValue, Value: Null{}})
+}
+
+// Value writes a complete value tree, such as one returned by Parse.
+func (e *Encoder) Value(v Value) error {
+	// Check the position once, then the tree itself cannot be misplaced.
+	if err := e.check(Token{Kind: ScalarValue}); err != nil {
+		return err
+	}
+	if err := checkTree(v); err != nil {
+		return err
+	}
+	e.writeTree(v)
+	return nil
+}
+
+// Flush writes any buffered output to the underlying writer.
+func (e *Encoder) Flush() error {
+	return e.f.w.Flush()
+}
+
+// Close flushes the output and fails if the document is not complete.
+func (e *Encoder) Close() error {
+	if err := e.Flush(); err != nil {
+		return err
+	}
+	if !e.done {
+		return fmt.Errorf("%w: document is incomplete with %d open containers", ErrEncoderState, len(e.stack))
+	}
+	return nil
+}
+
+func (e *Encoder) writeTree(v Value) {
+	switch v := v.(type) {
+	case *Object:
+		e.apply(Token{Kind: ObjectStart})
+		for _, m := range v.Members {
+			e.apply(Token{Kind: ObjectKey, Value: NewString(m.Key.Value)})
+			e.writeTree(m.Value)
+		}
+		e.apply(Token{Kind: ObjectEnd})
+	case *Array:
+		e.apply(Token{Kind: ArrayStart})
+		for _, el := range v.Elements {
+			e.writeTree(el)
+		}
+		e.apply(Token{Kind: ArrayEnd})
+	case String:
+		e.apply(Token{Kind: ScalarValue, Value: NewString(v.Value)})
+	default:
+		e.apply(Token{Kind: ScalarValue, Value: v})
+	}
+}
+
+// checkTree fails for values inside v that have no JSON form.
+func checkTree(v Value) error {
+	switch v := v.(type) {
+	case *Object:
+		for _, m := range v.Members {
+			if err := checkTree(m.Value); err != nil {
+				return err
+			}
+		}
+	case *Array:
+		for _, el := range v.Elements {
+			if err := checkTree(el); err != nil {
+				return err
+			}
+		}
+	case Number:
+		if !isNumberLiteral(v.Literal) {
+			return fmt.Errorf("%w: %q is not a JSON number", ErrEncoderState, v.Literal)
+		}
+	case String, Bool, Null:
+	default:
+		return fmt.Errorf("%w: unsupported value %T", ErrEncoderState, v)
+	}
+	return nil
+}
+
+func (e *Encoder) write(tok Token) error {
+	if err := e.check(tok); err != nil {
+		return err
+	}
+	if n, ok := tok.Value.(Number); ok && !isNumberLiteral(n.Literal) {
+		return fmt.Errorf("%w: %q is not a JSON number", ErrEncoderState, n.Literal)
+	}
+	e.apply(tok)
+	return nil
+}
+
+// check reports whether tok may be written next.
+func (e *Encoder) check(tok Token) error {
+	if e.done {
+		return fmt.Errorf("%w: %s after the end of the document", ErrEncoderState, tok.Kind)
+	}
+	isValue := tok.Kind == ObjectStart || tok.Kind == ArrayStart || tok.Kind == ScalarValue
+	var ok bool
+	switch {
+	case len(e.stack) == 0:
+		ok = isValue
+	case e.stack[len(e.stack)-1].kind == ArrayKind:
+		ok = isValue || tok.Kind == ArrayEnd
+	case e.stack[len(e.stack)-1].needKey:
+		ok = tok.Kind == ObjectKey || tok.Kind == ObjectEnd
+	default:
+		ok = isValue
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s not allowed %s", ErrEncoderState, tok.Kind, e.where())
+	}
+	return nil
+}
+
+func (e *Encoder) where() string {
+	if len(e.stack) == 0 {
+		return "at the top level"
+	}
+	top := e.stack[len(e.stack)-1]
+	switch {
+	case top.kind == ArrayKind:
+		return "in an array"
+	case top.needKey:
+		return "where an object expects a key"
+	}
+	return "where an object expects a value"
+}
+
+// apply writes a token that has passed check and updates the structure.
+func (e *Encoder) apply(tok Token) {
+	e.f.token(tok)
+	switch tok.Kind {
+	case ObjectStart:
+		e.stack = append(e.stack, encoderFrame{kind: ObjectKind, needKey: true})
+		return
+	case ArrayStart:
+		e.stack = append(e.stack, encoderFrame{kind: ArrayKind})
+		return
+	case ObjectKey:
+		e.stack[len(e.stack)-1].needKey = false
+		return
+	case ObjectEnd, ArrayEnd:
+		e.stack = e.stack[:len(e.stack)-1]
+	}
+	// A value or container has been completed.
+	if len(e.stack) == 0 {
+		e.done = true
+	} else if top := &e.stack[len(e.stack)-1]; top.kind == ObjectKind {
+		top.needKey = true
+	}
+}
+
+// isNumberLiteral reports whether s is a number as JSON writes it.
+func isNumberLiteral(s string) bool {
+	i := 0
+	digits := func() int {
+		start := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		return i - start
+	}
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+	if i < len(s) && s[i] == '0' {
+		i++
+	} else if digits() == 0 {
+		return false
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		if digits() == 0 {
+			return false
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return i == len(s)
+}
@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	calls := []error{
+		e.BeginObject(),
+		e.Key("name"), e.String("quote \" and \\ and \n and \u0001 and é"),
+		e.Key("count"), e.Int(-42),
+		e.Key("ratio"), e.Float(0.25),
+		e.Key("big"), e.Number(Number{Literal: "1e400"}),
+		e.Key("items"), e.BeginArray(),
+		e.Bool(true), e.Null(), e.BeginObject(), e.EndObject(), e.BeginArray(), e.EndArray(),
+		e.EndArray(),
+		e.Key("tree"), e.Value(&Object{Members: []Member{{Key: NewString("a\tb"), Value: String{Value: `x"y`, Raw: "not escaped"}}}}),
+		e.EndObject(),
+		e.Close(),
+	}
+	for i, err := range calls {
+		if err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i, err)
+		}
+	}
+	want := `{"name":"quote \" and \\ and \n and \u0001 and é","count":-42,"ratio":0.25,"big":1e400,` +
+		`"items":[true,null,{},[]],"tree":{"a\tb":"x\"y"}}`
+	if buf.String() != want {
+		t.Fatalf("unexpected output.\nexpected: %s\nactual: %s", want, buf.String())
+	}
+	if !json.Valid(buf.Bytes()) {
+		t.Fatalf("output is not valid JSON")
+	}
+}
+
+func TestEncoderIndent(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	e.SetIndent("  ")
+	_ = e.BeginArray()
+	for i := range 2 {
+		_ = e.BeginObject()
+		_ = e.Key("i")
+		_ = e.Int(int64(i))
+		_ = e.EndObject()
+	}
+	_ = e.EndArray()
+	if err := e.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "[\n  {\n    \"i\": 0\n  },\n  {\n    \"i\": 1\n  }\n]"
+	if buf.String() != want {
+		t.Fatalf("unexpected output.\nexpected: %s\nactual: %s", want, buf.String())
+	}
+}
+
+func TestEncoderMisuse(t *testing.T) {
+	tests := []struct {
+		name  string
+		calls func(e *Encoder) error
+		msg   string
+	}{
+		{"key at top level", func(e *Encoder) error { return e.Key("a") }, "object key not allowed at the top level"},
+		{"key in array", func(e *Encoder) error {
+			_ = e.BeginArray()
+			return e.Key("a")
+		}, "object key not allowed in an array"},
+		{"value without key", func(e *Encoder) error {
+			_ = e.BeginObject()
+			return e.Int(1)
+		}, "scalar value not allowed where an object expects a key"},
+		{"end after key", func(e *Encoder) error {
+			_ = e.BeginObject()
+			_ = e.Key("a")
+			return e.EndObject()
+		}, "object end not allowed where an object expects a value"},
+		{"mismatched end", func(e *Encoder) error {
+			_ = e.BeginObject()
+			return e.EndArray()
+		}, "array end not allowed where an object expects a key"},
+		{"second document", func(e *Encoder) error {
+			_ = e.Null()
+			return e.Null()
+		}, "scalar value after the end of the document"},
+		{"NaN", func(e *Encoder) error { return e.Float(math.NaN()) }, "NaN is not a JSON number"},
+		{"bad literal", func(e *Encoder) error { return e.Number(Number{Literal: "01"}) }, `"01" is not a JSON number`},
+		{"bad literal in tree", func(e *Encoder) error {
+			return e.Value(&Array{Elements: []Value{Number{Literal: "Infinity"}}})
+		}, `"Infinity" is not a JSON number`},
+		{"incomplete", func(e *Encoder) error {
+			_ = e.BeginArray()
+			return e.Close()
+		}, "document is incomplete with 1 open containers"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			e := NewEncoder(&buf)
+			err := tc.calls(e)
+			if !errors.Is(err, ErrEncoderState) || !strings.HasSuffix(err.Error(), tc.msg) {
+				t.Fatalf("unexpected error.\nexpected: ...%s\nactual: %v", tc.msg, err)
+			}
+		})
+	}
+}
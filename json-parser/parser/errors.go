@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalid is returned when the input is not a valid JSON document.
+// Every *SyntaxError wraps it, so errors.Is(err, ErrInvalid) can be used to
+// tell syntax problems apart from read errors.
+var ErrInvalid = errors.New("invalid JSON")
+
+// Position is a location in the input. Offset is counted in bytes from the
+// start of the input, Line and Column start at 1 and Column counts runes.
+type Position struct {
+	Offset int64
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// SyntaxError describes where and why the input stopped being valid JSON.
+type SyntaxError struct {
+	Position
+	// Expected lists the tokens that would have been accepted at Position.
+	Expected []string
+	// Found describes what was actually read, e.g. `'x'` or "end of input".
+	Found string
+	// Reason is set when the problem is not just an unexpected token, such
+	// as a leading zero in a number.
+	Reason string
+}
+
+// Message returns the description of the error without its position.
+func (e *SyntaxError) Message() string {
+	var sb strings.Builder
+	if e.Reason != "" {
+		sb.WriteString(e.Reason)
+		if len(e.Expected) > 0 || e.Found != "" {
+			sb.WriteString(": ")
+		}
+	}
+	if len(e.Expected) > 0 {
+		sb.WriteString("expected ")
+		sb.WriteString(joinAlternatives(e.Expected))
+		if e.Found != "" {
+			sb.WriteString(", ")
+		}
+	}
+	if e.Found != "" {
+		sb.WriteString("found ")
+		sb.WriteString(e.Found)
+	}
+	return sb.String()
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message())
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return ErrInvalid
+}
+
+// ErrLimitExceeded is wrapped by every *LimitError.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// LimitError reports that the input went over one of the limits set in
+// Options.
+type LimitError struct {
+	Position
+	// Limit names the limit, e.g. "nesting depth".
+	Limit string
+	Max   int64
+}
+
+// Message returns the description of the error without its position.
+func (e *LimitError) Message() string {
+	return fmt.Sprintf("%s exceeds the limit of %d", e.Limit, e.Max)
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message())
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// ErrorList is returned in recovery mode when the document has errors. It
+// holds them in input order; all but the last are *SyntaxErrors.
+type ErrorList []error
+
+func (l ErrorList) Error() string {
+	if len(l) == 1 {
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
+
+func (l ErrorList) Unwrap() []error {
+	return l
+}
+
+const endOfInput = "end of input"
+
+// describeRune quotes r the way it is shown in error messages.
+func describeRune(r rune) string {
+	return strconv.QuoteRune(r)
+}
+
+func describeRunes(runes []rune) []string {
+	s := make([]string, 0, len(runes))
+	for _, r := range runes {
+		s = append(s, describeRune(r))
+	}
+	return s
+}
+
+func joinAlternatives(alts []string) string {
+	switch len(alts) {
+	case 1:
+		return alts[0]
+	case 2:
+		return alts[0] + " or " + alts[1]
+	}
+	return strings.Join(alts[:len(alts)-1], ", ") + " or " + alts[len(alts)-1]
+}
@@ -0,0 +1,204 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// formatter writes tokens back out as JSON text. Strings and numbers are
+// written exactly as they appeared in the input.
+type formatter struct {
+	w      *bufio.Writer
+	indent string
+	depth  int
+	// first is set right after a container is opened, before its first
+	// member or element has been written.
+	first bool
+	// afterKey is set between a key and its value.
+	afterKey bool
+	// err records the first number that JSON cannot represent.
+	err error
+}
+
+// Format reads the document from d and writes it to w with every member and
+// element on its own line, indented by indent per level of nesting. An
+// empty indent removes all insignificant whitespace instead. Nothing is
+// buffered beyond the current token, so large documents can be formatted
+// as a stream; on a syntax error w holds the output written so far. The
+// NaN and Infinity of JSON5 have no JSON spelling and are an error.
+func Format(w io.Writer, d *Decoder, indent string) error {
+	f := &formatter{w: bufio.NewWriter(w), indent: indent}
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if n, ok := tok.Value.(Number); ok && !isNumberLiteral(n.Literal) {
+			return &SyntaxError{Position: tok.Pos, Reason: fmt.Sprintf("%s cannot be written as JSON", n.Literal)}
+		}
+		f.token(tok)
+	}
+	return f.w.Flush()
+}
+
+// WriteValue writes v to w in the same layout as Format. Like Format, it
+// fails on numbers such as NaN that JSON cannot represent.
+func WriteValue(w io.Writer, v Value, indent string) error {
+	f := &formatter{w: bufio.NewWriter(w), indent: indent}
+	f.value(v)
+	if f.err != nil {
+		return f.err
+	}
+	return f.w.Flush()
+}
+
+// Marshal returns v as JSON text without insignificant whitespace.
+func Marshal(v Value) []byte {
+	var buf bytes.Buffer
+	_ = WriteValue(&buf, v, "")
+	return buf.Bytes()
+}
+
+func (f *formatter) value(v Value) {
+	switch v := v.(type) {
+	case *Object:
+		f.token(Token{Kind: ObjectStart})
+		for _, m := range v.Members {
+			f.token(Token{Kind: ObjectKey, Value: m.Key})
+			f.value(m.Value)
+		}
+		f.token(Token{Kind: ObjectEnd})
+	case *Array:
+		f.token(Token{Kind: ArrayStart})
+		for _, e := range v.Elements {
+			f.value(e)
+		}
+		f.token(Token{Kind: ArrayEnd})
+	default:
+		f.token(Token{Kind: ScalarValue, Value: v})
+	}
+}
+
+func (f *formatter) token(tok Token) {
+	switch tok.Kind {
+	case ObjectEnd, ArrayEnd:
+		f.depth--
+		if !f.first {
+			f.newline()
+		}
+		f.first = false
+		if tok.Kind == ObjectEnd {
+			f.w.WriteByte('}')
+		} else {
+			f.w.WriteByte(']')
+		}
+		return
+	}
+
+	if f.afterKey {
+		f.afterKey = false
+	} else if f.depth > 0 {
+		if !f.first {
+			f.w.WriteByte(',')
+		}
+		f.newline()
+	}
+	f.first = false
+
+	switch tok.Kind {
+	case ObjectStart:
+		f.w.WriteByte('{')
+		f.depth++
+		f.first = true
+	case ArrayStart:
+		f.w.WriteByte('[')
+		f.depth++
+		f.first = true
+	case ObjectKey:
+		f.scalar(tok.Value)
+		f.w.WriteByte(':')
+		if f.indent != "" {
+			f.w.WriteByte(' ')
+		}
+		f.afterKey = true
+	case ScalarValue:
+		f.scalar(tok.Value)
+	}
+}
+
+func (f *formatter) scalar(v Value) {
+	switch v := v.(type) {
+	case String:
+		f.w.WriteByte('"')
+		f.w.WriteString(v.Raw)
+		f.w.WriteByte('"')
+	case Number:
+		if f.err == nil && !isNumberLiteral(v.Literal) {
+			f.err = fmt.Errorf("number %s cannot be written as JSON", v.Literal)
+		}
+		f.w.WriteString(v.Literal)
+	case Bool:
+		if v {
+			f.w.WriteString("true")
+		} else {
+			f.w.WriteString("false")
+		}
+	case Null:
+		f.w.WriteString("null")
+	}
+}
+
+func (f *formatter) newline() {
+	if f.indent == "" {
+		return
+	}
+	f.w.WriteByte('\n')
+	for i := 0; i < f.depth; i++ {
+		f.w.WriteString(f.indent)
+	}
+}
+
+// NewString returns a String holding s, with Raw set to its escaped form.
+func NewString(s string) String {
+	return String{Value: s, Raw: escapeString(s)}
+}
+
+// escapeString escapes quotes, backslashes and control characters so that
+// s can be written between double quotes.
+func escapeString(s string) string {
+	const hex = "0123456789abcdef"
+	var sb strings.Builder
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				sb.WriteString(`\u00`)
+				sb.WriteByte(hex[r>>4])
+				sb.WriteByte(hex[r&0xF])
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		indent string
+		want   string
+	}{
+		{
+			name:   "minify keeps number text and escapes",
+			input:  "{ \"a\\u0041\" : [ 1.50E+3 , -0 ] ,\n \"b\" : \"\\/\" }",
+			indent: "",
+			want:   `{"a\u0041":[1.50E+3,-0],"b":"\/"}`,
+		},
+		{
+			name:   "indent nested",
+			input:  `{"a":[1,{"b":null}],"c":{}}`,
+			indent: "  ",
+			want:   "{\n  \"a\": [\n    1,\n    {\n      \"b\": null\n    }\n  ],\n  \"c\": {}\n}",
+		},
+		{
+			name:   "empty containers",
+			input:  `[ [ ] , { } ]`,
+			indent: "\t",
+			want:   "[\n\t[],\n\t{}\n]",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := Format(&sb, NewDecoder(strings.NewReader(tc.input)), tc.indent); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sb.String() != tc.want {
+				t.Fatalf("unexpected output.\nexpected: %s\nactual: %s", tc.want, sb.String())
+			}
+
+			v, err := Parse(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sb.Reset()
+			if err := WriteValue(&sb, v, tc.indent); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sb.String() != tc.want {
+				t.Fatalf("unexpected WriteValue output.\nexpected: %s\nactual: %s", tc.want, sb.String())
+			}
+		})
+	}
+}
+
+func TestNewString(t *testing.T) {
+	s := NewString("a\"b\\c\n\x01é")
+	want := `a\"b\\c\n\u0001é`
+	if s.Raw != want {
+		t.Fatalf("unexpected raw text.\nexpected: %s\nactual: %s", want, s.Raw)
+	}
+	v, err := Parse(strings.NewReader(`["` + s.Raw + `"]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := v.(*Array).Elements[0].(String); got != s {
+		t.Fatalf("escaped string does not round-trip: %#v", got)
+	}
+}
+
+func TestFormatRejectsNonJSONNumbers(t *testing.T) {
+	opts := Options{Dialect: DialectJSON5}
+	for _, input := range []string{`{a: NaN}`, `[1, -Infinity]`} {
+		var sb strings.Builder
+		if err := Format(&sb, NewDecoderWithOptions(strings.NewReader(input), opts), ""); err == nil {
+			t.Fatalf("expected an error for %s, got %s", input, sb.String())
+		}
+
+		v, err := ParseWithOptions(strings.NewReader(input), opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := WriteValue(&sb, v, ""); err == nil {
+			t.Fatalf("expected WriteValue to fail for %s", input)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// addSeeds adds the fixture files and a few edge cases to the corpus.
+func addSeeds(f *testing.F) {
+	files, _ := filepath.Glob("../test_files/step*/*.json")
+	for _, name := range files {
+		if data, err := os.ReadFile(name); err == nil {
+			f.Add(data)
+		}
+	}
+	for _, s := range []string{
+		``, `0`, `-0.0e+1`, `"\ud800"`, `{"a":1,"a":2}`, `[1,]`, "[٣]", "\t[ ]\r\n", "\v[]",
+		`[1e400]`, `{"":""}`, `"\u0000"`, "\"\xff\"", `[[[[[[]]]]]]`, `nul`, `{"a" 1}`,
+	} {
+		f.Add([]byte(s))
+	}
+}
+
+// FuzzParse checks that Parse accepts exactly the documents encoding/json
+// accepts, and that an accepted document survives a Marshal round trip.
+func FuzzParse(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v, err := ParseWithOptions(bytes.NewReader(data), Options{RFC: RFC8259})
+		want := json.Valid(data)
+		if (err == nil) != want {
+			t.Fatalf("Parse(%q) returned %v, encoding/json accepts: %v", data, err, want)
+		}
+		if err != nil {
+			if !errors.Is(err, ErrInvalid) {
+				t.Fatalf("Parse(%q) returned an error not wrapping ErrInvalid: %v", data, err)
+			}
+			return
+		}
+		out := Marshal(v)
+		if !json.Valid(out) {
+			t.Fatalf("Marshal produced invalid JSON %q for %q", out, data)
+		}
+		back, err := ParseWithOptions(bytes.NewReader(out), Options{RFC: RFC8259})
+		if err != nil || !Equal(v, back) {
+			t.Fatalf("round trip of %q through %q failed: %v", data, out, err)
+		}
+
+		// RFC 4627 additionally requires an object or array at the top level.
+		_, err4627 := Parse(bytes.NewReader(data))
+		if isContainer := v.Kind() == ObjectKind || v.Kind() == ArrayKind; (err4627 == nil) != isContainer {
+			t.Fatalf("RFC 4627 Parse(%q) returned %v for a top-level %s", data, err4627, v.Kind())
+		}
+	})
+}
+
+// FuzzDecoder checks the token stream of the Decoder against encoding/json
+// and against Format, which must reproduce a document that is accepted.
+func FuzzDecoder(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		d := NewDecoderWithOptions(bytes.NewReader(data), Options{RFC: RFC8259})
+		depth := 0
+		var err error
+		for {
+			var tok Token
+			if tok, err = d.Token(); err != nil {
+				break
+			}
+			switch tok.Kind {
+			case ObjectStart, ArrayStart:
+				depth++
+			case ObjectEnd, ArrayEnd:
+				depth--
+			}
+			if depth != d.Depth() || depth < 0 {
+				t.Fatalf("depth %d after %v, Decoder reports %d", depth, tok.Kind, d.Depth())
+			}
+		}
+		want := json.Valid(data)
+		if (err == io.EOF) != want {
+			t.Fatalf("Decoder on %q stopped with %v, encoding/json accepts: %v", data, err, want)
+		}
+		if !want {
+			return
+		}
+		var buf bytes.Buffer
+		if err := Format(&buf, NewDecoderWithOptions(bytes.NewReader(data), Options{RFC: RFC8259}), "  "); err != nil {
+			t.Fatalf("Format(%q) failed: %v", data, err)
+		}
+		if !json.Valid(buf.Bytes()) {
+			t.Fatalf("Format produced invalid JSON %q for %q", buf.Bytes(), data)
+		}
+	})
+}
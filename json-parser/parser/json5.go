@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"io"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// skipComment consumes a // or /* */ comment starting at the next rune and
+// reports whether there was one. A line comment may end with the input, a
+// block comment must be closed.
+func (p *parser) skipComment() (bool, error) {
+	start, err := p.reader.Peek(2)
+	if err != nil || start[0] != '/' || (start[1] != '/' && start[1] != '*') {
+		return false, nil
+	}
+	// Reading may reuse the peeked bytes, so look at them first.
+	block := start[1] == '*'
+	for range 2 {
+		if _, err := p.readRune(); err != nil {
+			return true, err
+		}
+	}
+	prev := rune(0)
+	for {
+		r, err := p.readRune()
+		if err == io.EOF && block {
+			return true, &SyntaxError{Position: p.last, Reason: "unterminated block comment", Expected: []string{`"*/"`}, Found: endOfInput}
+		}
+		if err == io.EOF {
+			return true, nil
+		}
+		if err != nil {
+			return true, err
+		}
+		if block && prev == '*' && r == '/' {
+			return true, nil
+		}
+		if !block && r == '\n' {
+			return true, nil
+		}
+		prev = r
+	}
+}
+
+// parseIdentifier reads a JSON5 member name written without quotes. The
+// returned String has its Raw text escaped as JSON.
+func (p *parser) parseIdentifier() (String, error) {
+	var sb strings.Builder
+	for {
+		r, err := p.readRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return String{}, err
+		}
+		if !isIdentifierStart(r) && !unicode.IsDigit(r) {
+			p.unreadRune()
+			break
+		}
+		sb.WriteRune(r)
+		if err := p.checkLength(sb.Len(), p.opts.MaxStringLength, "string length"); err != nil {
+			return String{}, err
+		}
+	}
+	return NewString(sb.String()), nil
+}
+
+// parseJSON5Number reads a number that may also carry a leading '+', be
+// written in hexadecimal or be Infinity or NaN. Hexadecimal numbers are
+// converted to a decimal Literal; Infinity and NaN keep their spelling.
+func (p *parser) parseJSON5Number() (Number, error) {
+	sign := ""
+	r, err := p.readRune()
+	if err != nil {
+		return Number{}, p.unexpected(err, "digit")
+	}
+	if r == '+' || r == '-' {
+		if r == '-' {
+			sign = "-"
+		}
+		if r, err = p.readRune(); err != nil {
+			return Number{}, p.unexpected(err, "digit")
+		}
+		if r == '+' || r == '-' {
+			return Number{}, p.unexpectedRune(r, "digit")
+		}
+	}
+	switch r {
+	case 'I':
+		if err := p.parseSequence("nfinity"); err != nil {
+			return Number{}, err
+		}
+		return Number{Literal: sign + "Infinity"}, nil
+	case 'N':
+		if err := p.parseSequence("aN"); err != nil {
+			return Number{}, err
+		}
+		return Number{Literal: "NaN"}, nil
+	}
+	// A Peek after reading would stop the rune from being unread for
+	// parseNumber, so step back first and look for "0x" ahead of it.
+	p.unreadRune()
+	if start, err := p.reader.Peek(2); err == nil && start[0] == '0' && (start[1] == 'x' || start[1] == 'X') {
+		p.readRune()
+		p.readRune()
+		return p.parseHexNumber(sign)
+	}
+	n, err := p.parseNumber()
+	if err != nil {
+		return Number{}, err
+	}
+	n.Literal = sign + n.Literal
+	return n, nil
+}
+
+// parseHexNumber reads the digits following "0x".
+func (p *parser) parseHexNumber(sign string) (Number, error) {
+	var digits strings.Builder
+	for {
+		r, err := p.readRune()
+		if err != nil {
+			if err == io.EOF && digits.Len() > 0 {
+				break
+			}
+			return Number{}, p.unexpected(err, "hex digit")
+		}
+		if !isHexDigit(r) {
+			if digits.Len() == 0 {
+				return Number{}, p.unexpectedRune(r, "hex digit")
+			}
+			p.unreadRune()
+			break
+		}
+		digits.WriteRune(r)
+		if err := p.checkLength(digits.Len()+2, p.opts.MaxNumberLength, "number length"); err != nil {
+			return Number{}, err
+		}
+	}
+	n, _ := new(big.Int).SetString(digits.String(), 16)
+	return Number{Literal: sign + n.String()}, nil
+}
+
+func isIdentifierStart(r rune) bool {
+	return r == '$' || r == '_' || unicode.IsLetter(r)
+}
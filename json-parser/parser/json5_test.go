@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDialects(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		input   string
+		want    Value
+	}{
+		{"line comment", DialectJSONC, "// header\n[1 // one\n]", &Array{Elements: []Value{Number{Literal: "1"}}}},
+		{"block comment", DialectJSONC, "/* a */{/**/\"a\"/* b */:true}", &Object{Members: []Member{{Key: String{Value: "a", Raw: "a"}, Value: Bool(true)}}}},
+		{"line comment at end", DialectJSONC, "{} // end", &Object{}},
+		{"trailing comma in array", DialectJSONC, "[1, 2,]", &Array{Elements: []Value{Number{Literal: "1"}, Number{Literal: "2"}}}},
+		{"trailing comma in object", DialectJSONC, `{"a": null,}`, &Object{Members: []Member{{Key: String{Value: "a", Raw: "a"}, Value: Null{}}}}},
+		{"single quotes", DialectJSON5, `['it\'s "x"']`, &Array{Elements: []Value{String{Value: `it's "x"`, Raw: `it's \"x\"`}}}},
+		{"unquoted key", DialectJSON5, "{$key_1: 1}", &Object{Members: []Member{{Key: String{Value: "$key_1", Raw: "$key_1"}, Value: Number{Literal: "1"}}}}},
+		{"hex", DialectJSON5, "[0x1F, -0Xff]", &Array{Elements: []Value{Number{Literal: "31"}, Number{Literal: "-255"}}}},
+		{"zero", DialectJSON5, "{a: 0, b: [0.5, -0.25, +0, 0e1]}", &Object{Members: []Member{
+			{Key: String{Value: "a", Raw: "a"}, Value: Number{Literal: "0"}},
+			{Key: String{Value: "b", Raw: "b"}, Value: &Array{Elements: []Value{
+				Number{Literal: "0.5"}, Number{Literal: "-0.25"}, Number{Literal: "0"}, Number{Literal: "0e1"},
+			}}},
+		}}},
+		{"special numbers", DialectJSON5, "[Infinity, -Infinity, NaN, +1.5]", &Array{Elements: []Value{
+			Number{Literal: "Infinity"}, Number{Literal: "-Infinity"}, Number{Literal: "NaN"}, Number{Literal: "1.5"},
+		}}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseWithOptions(strings.NewReader(tc.input), Options{Dialect: tc.dialect})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tc.want) {
+				t.Fatalf("unexpected output.\nexpected: %#v\nactual: %#v", tc.want, actual)
+			}
+			if _, err := Parse(strings.NewReader(tc.input)); err == nil {
+				t.Fatalf("expected strict JSON to reject %q", tc.input)
+			}
+		})
+	}
+}
+
+func TestParseDialectsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		input   string
+	}{
+		{"single quotes in jsonc", DialectJSONC, `['a']`},
+		{"hex in jsonc", DialectJSONC, `[0x10]`},
+		{"double trailing comma", DialectJSONC, `[1,,]`},
+		{"lone comma", DialectJSONC, `[,]`},
+		{"unterminated comment", DialectJSONC, `[1 /* ]`},
+		{"unterminated comment after value", DialectJSONC, `{} /* x`},
+		{"unterminated comment in json5", DialectJSON5, `{a: 1} /*/`},
+		{"double sign", DialectJSON5, `[+-1]`},
+		{"empty hex", DialectJSON5, `[0x]`},
+		{"key starting with digit", DialectJSON5, `{1a: 1}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseWithOptions(strings.NewReader(tc.input), Options{Dialect: tc.dialect})
+			if !errors.Is(err, ErrInvalid) {
+				t.Fatalf("expected ErrInvalid, got %v", err)
+			}
+		})
+	}
+	_, err := ParseWithOptions(strings.NewReader("{} /* x"), Options{Dialect: DialectJSONC})
+	want := `line 1, column 8: unterminated block comment: expected "*/", found end of input`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error.\nexpected: %s\nactual: %v", want, err)
+	}
+}
@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// NumberMode selects the representation numbers must fit when decoding.
+type NumberMode int
+
+const (
+	// NumberExact accepts any number. The literal text is kept, and
+	// BigFloat and BigInt give its value without loss.
+	NumberExact NumberMode = iota
+	// NumberFloat64 rejects numbers outside the range of a float64.
+	NumberFloat64
+	// NumberInt64 rejects numbers that are not integers in the range of an
+	// int64.
+	NumberInt64
+)
+
+func (m NumberMode) String() string {
+	switch m {
+	case NumberExact:
+		return "exact"
+	case NumberFloat64:
+		return "float64"
+	case NumberInt64:
+		return "int64"
+	}
+	return "unknown"
+}
+
+// ParseNumberMode converts "exact", "float64" or "int64" to a NumberMode.
+func ParseNumberMode(s string) (NumberMode, error) {
+	switch s {
+	case "exact":
+		return NumberExact, nil
+	case "float64":
+		return NumberFloat64, nil
+	case "int64":
+		return NumberInt64, nil
+	}
+	return 0, fmt.Errorf("unsupported number mode %q, expected exact, float64 or int64", s)
+}
+
+// ErrNotInteger is returned by Int64 and BigInt for numbers with a
+// fractional part.
+var ErrNotInteger = errors.New("not an integer")
+
+// maxSafeInteger is the largest integer n such that every integer up to n
+// is exactly representable by a float64 (2^53 - 1).
+var maxSafeInteger = big.NewFloat(1<<53 - 1)
+
+// BigFloat returns the value of n with enough precision for every digit of
+// the literal. An exponent too large or too small for a big.Float, as in
+// 1e999999999, is reported with an error wrapping strconv.ErrRange.
+func (n Number) BigFloat() (*big.Float, error) {
+	f, err := n.parseBig()
+	if err != nil {
+		return nil, err
+	}
+	if f.IsInf() || f.Sign() == 0 && !n.isZero() {
+		return nil, fmt.Errorf("number %s is out of range: %w", n.Literal, strconv.ErrRange)
+	}
+	return f, nil
+}
+
+// parseBig is BigFloat without the range check, so huge exponents give
+// ±Inf and tiny ones 0.
+func (n Number) parseBig() (*big.Float, error) {
+	// Each decimal digit needs a little under 4 bits.
+	prec := max(uint(len(n.Literal))*4, numberPrec)
+	f, _, err := new(big.Float).SetPrec(prec).Parse(n.Literal, 10)
+	if err != nil {
+		return nil, fmt.Errorf("number %s: %w", n.Literal, err)
+	}
+	return f, nil
+}
+
+// isZero reports whether every digit before the exponent of n is 0.
+func (n Number) isZero() bool {
+	mantissa, _, _ := strings.Cut(strings.ToLower(n.Literal), "e")
+	return !strings.ContainsAny(mantissa, "123456789")
+}
+
+// integer returns the value of n if it is an integer.
+func (n Number) integer() (*big.Float, error) {
+	f, err := n.BigFloat()
+	if err != nil {
+		return nil, err
+	}
+	if !f.IsInt() {
+		return nil, fmt.Errorf("number %s: %w", n.Literal, ErrNotInteger)
+	}
+	return f, nil
+}
+
+// IsInteger reports whether n is an integer, however large. Unlike Int64
+// and BigInt, it accepts exponents beyond the range of a big.Float.
+func (n Number) IsInteger() bool {
+	f, err := n.parseBig()
+	return err == nil && (f.IsInf() || f.IsInt() && (f.Sign() != 0 || n.isZero()))
+}
+
+// BigInt returns the value of n if it is an integer. Literals such as 1.0
+// and 1e3 count as integers. To keep a short literal like 1e999999 from
+// taking up megabytes, the result may have no more bits than the larger of
+// 4 per byte of the literal and 65536.
+func (n Number) BigInt() (*big.Int, error) {
+	f, err := n.integer()
+	if err != nil {
+		return nil, err
+	}
+	if f.MantExp(nil) > max(4*len(n.Literal), maxIntBits) {
+		return nil, fmt.Errorf("number %s is too large for an integer: %w", n.Literal, strconv.ErrRange)
+	}
+	i, _ := f.Int(nil)
+	return i, nil
+}
+
+// maxIntBits is the size of integers BigInt converts whatever the length
+// of their literal.
+const maxIntBits = 1 << 16
+
+// Int64 returns the value of n if it is an integer that fits in an int64.
+// Overflow is reported with an error wrapping strconv.ErrRange.
+func (n Number) Int64() (int64, error) {
+	f, err := n.integer()
+	if err != nil {
+		return 0, err
+	}
+	i, acc := f.Int64()
+	if acc != big.Exact {
+		return 0, fmt.Errorf("number %s overflows int64: %w", n.Literal, strconv.ErrRange)
+	}
+	return i, nil
+}
+
+// Uint64 is like Int64 for a uint64.
+func (n Number) Uint64() (uint64, error) {
+	f, err := n.integer()
+	if err != nil {
+		return 0, err
+	}
+	i, acc := f.Uint64()
+	if acc != big.Exact {
+		return 0, fmt.Errorf("number %s overflows uint64: %w", n.Literal, strconv.ErrRange)
+	}
+	return i, nil
+}
+
+// IsSafeInteger reports whether n is an integer that a float64, and so a
+// JavaScript number, holds exactly.
+func (n Number) IsSafeInteger() bool {
+	f, err := n.integer()
+	return err == nil && new(big.Float).Abs(f).Cmp(maxSafeInteger) <= 0
+}
+
+// isUnsafeInteger reports whether n is an integer too large for a float64
+// to hold exactly, including one whose exponent overflows a big.Float.
+func (n Number) isUnsafeInteger() bool {
+	f, err := n.parseBig()
+	return err == nil && n.IsInteger() && new(big.Float).Abs(f).Cmp(maxSafeInteger) > 0
+}
@@ -0,0 +1,155 @@
+package parser
+
+import "fmt"
+
+// RFC selects which JSON specification the parser follows.
+type RFC int
+
+const (
+	// RFC4627 only accepts an object or an array as the top-level value.
+	RFC4627 RFC = iota
+	// RFC8259 accepts any value, including bare scalars, at the top level.
+	RFC8259
+)
+
+func (r RFC) String() string {
+	switch r {
+	case RFC4627:
+		return "4627"
+	case RFC8259:
+		return "8259"
+	}
+	return "unknown"
+}
+
+// ParseRFC converts "4627" or "8259" to an RFC.
+func ParseRFC(s string) (RFC, error) {
+	switch s {
+	case "4627":
+		return RFC4627, nil
+	case "8259":
+		return RFC8259, nil
+	}
+	return 0, fmt.Errorf("unsupported RFC %q, expected 4627 or 8259", s)
+}
+
+// DuplicateKeyPolicy decides what happens when an object repeats a key.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeysAllow accepts repeated keys; the last one wins.
+	DuplicateKeysAllow DuplicateKeyPolicy = iota
+	// DuplicateKeysWarn accepts repeated keys but reports each one to
+	// Options.Warn.
+	DuplicateKeysWarn
+	// DuplicateKeysError rejects the document at the first repeated key.
+	DuplicateKeysError
+)
+
+func (p DuplicateKeyPolicy) String() string {
+	switch p {
+	case DuplicateKeysAllow:
+		return "allow"
+	case DuplicateKeysWarn:
+		return "warn"
+	case DuplicateKeysError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// ParseDuplicateKeyPolicy converts "allow", "warn" or "error" to a
+// DuplicateKeyPolicy.
+func ParseDuplicateKeyPolicy(s string) (DuplicateKeyPolicy, error) {
+	switch s {
+	case "allow":
+		return DuplicateKeysAllow, nil
+	case "warn":
+		return DuplicateKeysWarn, nil
+	case "error":
+		return DuplicateKeysError, nil
+	}
+	return 0, fmt.Errorf("unsupported duplicate key policy %q, expected allow, warn or error", s)
+}
+
+// Dialect selects the flavour of JSON that is accepted.
+type Dialect int
+
+const (
+	// DialectJSON is strict JSON.
+	DialectJSON Dialect = iota
+	// DialectJSONC adds // and /* */ comments and trailing commas.
+	DialectJSONC
+	// DialectJSON5 adds to DialectJSONC single-quoted strings, unquoted
+	// keys, hexadecimal numbers, a leading '+' and Infinity and NaN.
+	DialectJSON5
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case DialectJSON:
+		return "json"
+	case DialectJSONC:
+		return "jsonc"
+	case DialectJSON5:
+		return "json5"
+	}
+	return "unknown"
+}
+
+// ParseDialect converts "json", "jsonc" or "json5" to a Dialect.
+func ParseDialect(s string) (Dialect, error) {
+	switch s {
+	case "json":
+		return DialectJSON, nil
+	case "jsonc":
+		return DialectJSONC, nil
+	case "json5":
+		return DialectJSON5, nil
+	}
+	return 0, fmt.Errorf("unsupported dialect %q, expected json, jsonc or json5", s)
+}
+
+// Options control how strictly a document is checked. The zero value
+// follows RFC 4627, accepts strict JSON only, accepts duplicate keys and
+// sets no limits.
+type Options struct {
+	RFC           RFC
+	Dialect       Dialect
+	DuplicateKeys DuplicateKeyPolicy
+	// StrictUnicode rejects malformed UTF-8 in the input and \u escapes
+	// holding an unpaired surrogate instead of decoding them as U+FFFD.
+	StrictUnicode bool
+	// Numbers selects the representation every number must fit.
+	Numbers NumberMode
+	// WarnUnsafeIntegers passes a warning to Warn for each integer beyond
+	// ±(2^53 - 1), which JavaScript consumers cannot hold exactly.
+	WarnUnsafeIntegers bool
+
+	// Limits for untrusted input; zero means unlimited. Exceeding one
+	// fails with a *LimitError.
+	//
+	// MaxDepth is the number of objects and arrays that may be open at
+	// once, MaxStringLength and MaxNumberLength count bytes of input text
+	// (escapes included, quotes excluded) and MaxDocumentSize counts all
+	// bytes read.
+	MaxDepth        int
+	MaxStringLength int
+	MaxNumberLength int
+	MaxDocumentSize int64
+
+	// Recover keeps going after a syntax error inside an object or array
+	// by skipping to the next ',' or closing bracket of the container, so
+	// that all errors are reported at once in an ErrorList. MaxErrors
+	// stops the decoder after that many errors; zero means no maximum.
+	Recover   bool
+	MaxErrors int
+
+	// DisallowUnknownFields makes UnmarshalWithOptions fail for object
+	// members that match no field of the target struct.
+	DisallowUnknownFields bool
+
+	// Warn is called with problems that do not make the document invalid.
+	// Warnings are dropped when it is nil.
+	Warn func(err error)
+}
@@ -0,0 +1,474 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// parser scans the individual tokens of a document for a Decoder.
+type parser struct {
+	reader *bufio.Reader
+	// pos is the position of the next rune, last the position of the rune
+	// returned by the latest readRune so that it can be unread.
+	pos  Position
+	last Position
+	opts Options
+	// limitErr is kept once the document grows past MaxDocumentSize so that
+	// every later read fails with it too.
+	limitErr error
+}
+
+// Parse reads a single JSON document from r and returns its value tree.
+// Only an object or an array is accepted at the top level, and nothing but
+// whitespace may follow it. The tree is built from Decoder tokens, so deep
+// nesting does not grow the goroutine stack.
+func Parse(r io.Reader) (Value, error) {
+	return ParseWithOptions(r, Options{})
+}
+
+// ParseWithOptions is like Parse but checks the document according to opts.
+func ParseWithOptions(r io.Reader, opts Options) (Value, error) {
+	d := NewDecoderWithOptions(r, opts)
+	var root Value
+	// containers and keys hold the open objects and arrays and, for each
+	// open object, the key of the member being read.
+	var containers []Value
+	var keys []String
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return root, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var v Value
+		switch tok.Kind {
+		case ObjectKey:
+			keys[len(keys)-1] = tok.Value.(String)
+			continue
+		case ObjectStart:
+			containers = append(containers, &Object{})
+			keys = append(keys, String{})
+			continue
+		case ArrayStart:
+			containers = append(containers, &Array{})
+			keys = append(keys, String{})
+			continue
+		case ObjectEnd, ArrayEnd:
+			v = containers[len(containers)-1]
+			containers = containers[:len(containers)-1]
+			keys = keys[:len(keys)-1]
+		case ScalarValue:
+			v = tok.Value
+		}
+		if len(containers) == 0 {
+			root = v
+			continue
+		}
+		switch parent := containers[len(containers)-1].(type) {
+		case *Object:
+			parent.Members = append(parent.Members, Member{Key: keys[len(keys)-1], Value: v})
+		case *Array:
+			parent.Elements = append(parent.Elements, v)
+		}
+	}
+}
+
+func (p *parser) parseNumber() (Number, error) {
+	var sb strings.Builder
+	isFirstDigit := true
+	isLeadingZero := false
+	signSeen := false
+	for {
+		r, err := p.readRune()
+		if err != nil {
+			// EOF is valid only if we've consumed at least one digit
+			if err == io.EOF && !isFirstDigit {
+				return Number{Literal: sb.String()}, nil
+			}
+			return Number{}, p.unexpected(err, "digit")
+		}
+		if !isDigit(r) {
+			// Only a single leading '-' is allowed
+			if r == '-' {
+				if !isFirstDigit || signSeen {
+					return Number{}, p.syntaxError("misplaced '-' in number", describeRune(r))
+				}
+				signSeen = true
+				sb.WriteRune(r)
+				continue
+			}
+			// No '+' allowed at any position in JSON numbers
+			if r == '+' || isFirstDigit {
+				return Number{}, p.unexpectedRune(r, "digit")
+			}
+			// If the number is a float, then it can have a single decimal point before the exponent
+			if r == '.' {
+				sb.WriteRune(r)
+				return p.parseFraction(&sb)
+			} else if r == 'e' || r == 'E' {
+				sb.WriteRune(r)
+				return p.parseExponent(&sb)
+			}
+			p.unreadRune()
+			return Number{Literal: sb.String()}, nil
+		}
+		if r == '0' && isFirstDigit {
+			isLeadingZero = true
+		}
+		if isLeadingZero && !isFirstDigit {
+			// No digits allowed after a leading zero unless followed by . or exponent (handled above)
+			return Number{}, p.syntaxError("leading zero in number", describeRune(r))
+		}
+		sb.WriteRune(r)
+		if err := p.checkLength(sb.Len(), p.opts.MaxNumberLength, "number length"); err != nil {
+			return Number{}, err
+		}
+		isFirstDigit = false
+	}
+}
+
+func (p *parser) parseFraction(sb *strings.Builder) (Number, error) {
+	isFirstDigit := true
+	for {
+		r, err := p.readRune()
+		if err != nil {
+			// Valid only if we saw at least one digit after the decimal point
+			if err == io.EOF && !isFirstDigit {
+				return Number{Literal: sb.String()}, nil
+			}
+			return Number{}, p.unexpected(err, "digit")
+		}
+		if !isDigit(r) {
+			// After decimal point, first character should be a digit
+			if isFirstDigit {
+				return Number{}, p.unexpectedRune(r, "digit")
+			}
+			if r == 'e' || r == 'E' {
+				sb.WriteRune(r)
+				return p.parseExponent(sb)
+			}
+			p.unreadRune()
+			return Number{Literal: sb.String()}, nil
+		}
+		sb.WriteRune(r)
+		if err := p.checkLength(sb.Len(), p.opts.MaxNumberLength, "number length"); err != nil {
+			return Number{}, err
+		}
+		isFirstDigit = false
+	}
+}
+
+func (p *parser) parseExponent(sb *strings.Builder) (Number, error) {
+	isFirstDigit := true
+	signSeen := false
+	for {
+		r, err := p.readRune()
+		if err != nil {
+			// Must have at least one digit in the exponent
+			if err == io.EOF && !isFirstDigit {
+				return Number{Literal: sb.String()}, nil
+			}
+			return Number{}, p.unexpected(err, "digit")
+		}
+		if !isDigit(r) {
+			if r == '+' || r == '-' {
+				// Allow at most one sign and only before the first digit
+				if !isFirstDigit || signSeen {
+					return Number{}, p.unexpectedRune(r, "digit")
+				}
+				signSeen = true
+				sb.WriteRune(r)
+				continue
+			}
+			if isFirstDigit {
+				return Number{}, p.unexpectedRune(r, "digit")
+			}
+			p.unreadRune()
+			return Number{Literal: sb.String()}, nil
+		}
+		sb.WriteRune(r)
+		if err := p.checkLength(sb.Len(), p.opts.MaxNumberLength, "number length"); err != nil {
+			return Number{}, err
+		}
+		isFirstDigit = false
+	}
+}
+
+// parseSequence consumes the rest of a literal such as null, true or false
+// whose first rune has already been read.
+func (p *parser) parseSequence(seq string) error {
+	for _, want := range seq {
+		r, err := p.readRune()
+		if err != nil {
+			return p.unexpected(err, describeRune(want))
+		}
+		if r != want {
+			return p.unexpectedRune(r, describeRune(want))
+		}
+	}
+	return nil
+}
+
+// parseString reads a string up to the closing quote, which is '"' except
+// for JSON5 single-quoted strings.
+func (p *parser) parseString(quote rune) (String, error) {
+	// Called after the opening quote has been consumed.
+	var raw, decoded strings.Builder
+	// Strings using JSON5-only syntax get a Raw text rewritten as JSON.
+	normalize := quote != '"'
+	for {
+		if err := p.checkLength(raw.Len(), p.opts.MaxStringLength, "string length"); err != nil {
+			return String{}, err
+		}
+		r, err := p.readRune()
+		if err != nil {
+			return String{}, p.unexpected(err, describeRune(quote))
+		}
+		// Closing quote (not escaped) ends the string
+		if r == quote {
+			if normalize {
+				return NewString(decoded.String()), nil
+			}
+			return String{Value: decoded.String(), Raw: raw.String()}, nil
+		}
+		// Unescaped control characters (U+0000 through U+001F) are not allowed in JSON strings
+		if r < 0x20 {
+			return String{}, p.syntaxError("control character in string", describeRune(r))
+		}
+		raw.WriteRune(r)
+		if r != '\\' {
+			decoded.WriteRune(r)
+			continue
+		}
+		escPos := p.last
+		esc, err := p.readRune()
+		if err != nil {
+			return String{}, p.unexpected(err, "escape character")
+		}
+		raw.WriteRune(esc)
+		switch esc {
+		case '"', '\\', '/':
+			decoded.WriteRune(esc)
+		case '\'':
+			if p.opts.Dialect != DialectJSON5 {
+				return String{}, p.syntaxError("invalid escape character", describeRune(esc))
+			}
+			decoded.WriteRune(esc)
+			normalize = true
+		case 'b':
+			decoded.WriteByte('\b')
+		case 'f':
+			decoded.WriteByte('\f')
+		case 'n':
+			decoded.WriteByte('\n')
+		case 'r':
+			decoded.WriteByte('\r')
+		case 't':
+			decoded.WriteByte('\t')
+		case 'u':
+			u, err := p.parseHex4(&raw)
+			if err != nil {
+				return String{}, err
+			}
+			if isHighSurrogate(u) {
+				// A high surrogate may be completed by a following \uXXXX escape
+				if low, ok := p.parseLowSurrogate(&raw); ok {
+					u = utf16.DecodeRune(u, low)
+				} else if u, err = p.unpairedSurrogate(u, escPos); err != nil {
+					return String{}, err
+				}
+			} else if utf16.IsSurrogate(u) {
+				if u, err = p.unpairedSurrogate(u, escPos); err != nil {
+					return String{}, err
+				}
+			}
+			decoded.WriteRune(u)
+		default:
+			return String{}, p.syntaxError("invalid escape character", describeRune(esc))
+		}
+	}
+}
+
+// parseHex4 reads exactly four hex digits of a \u escape.
+func (p *parser) parseHex4(raw *strings.Builder) (rune, error) {
+	var hex [4]byte
+	for i := range hex {
+		h, err := p.readRune()
+		if err != nil {
+			return 0, p.unexpected(err, "hex digit")
+		}
+		if !isHexDigit(h) {
+			return 0, p.unexpectedRune(h, "hex digit")
+		}
+		hex[i] = byte(h)
+	}
+	raw.Write(hex[:])
+	n, _ := strconv.ParseUint(string(hex[:]), 16, 32)
+	return rune(n), nil
+}
+
+// parseLowSurrogate consumes a \uXXXX escape holding a low surrogate if one
+// follows immediately, leaving the reader untouched otherwise.
+func (p *parser) parseLowSurrogate(raw *strings.Builder) (rune, bool) {
+	if max := p.opts.MaxDocumentSize; max > 0 && p.pos.Offset+6 > max {
+		return 0, false
+	}
+	next, err := p.reader.Peek(6)
+	if err != nil || next[0] != '\\' || next[1] != 'u' {
+		return 0, false
+	}
+	for _, h := range next[2:] {
+		if !isHexDigit(rune(h)) {
+			return 0, false
+		}
+	}
+	n, _ := strconv.ParseUint(string(next[2:]), 16, 32)
+	if n < 0xDC00 || n > 0xDFFF {
+		return 0, false
+	}
+	raw.Write(next)
+	_, _ = p.reader.Discard(6)
+	p.pos.Offset += 6
+	p.pos.Column += 6
+	return rune(n), true
+}
+
+// unpairedSurrogate returns the replacement for a surrogate escape at pos
+// that is not part of a pair, or an error if Options.StrictUnicode is set.
+func (p *parser) unpairedSurrogate(u rune, pos Position) (rune, error) {
+	if !p.opts.StrictUnicode {
+		return unicode.ReplacementChar, nil
+	}
+	reason := fmt.Sprintf("unpaired surrogate \\u%04X at byte offset %d", u, pos.Offset)
+	return 0, &SyntaxError{Position: pos, Reason: reason}
+}
+
+// skipWhitespace consumes whitespace, and comments outside plain JSON, up
+// to the next token. Running out of input is left for the caller to find.
+func (p *parser) skipWhitespace() error {
+	for {
+		if p.opts.Dialect != DialectJSON {
+			skipped, err := p.skipComment()
+			if err != nil {
+				return err
+			}
+			if skipped {
+				continue
+			}
+		}
+		r, err := p.readRune()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !p.isSpace(r) {
+			p.unreadRune()
+			return nil
+		}
+	}
+}
+
+// isSpace reports whether r is whitespace: space, tab, line feed and
+// carriage return in JSON, and any Unicode space, as well as the byte order
+// mark, in JSON5.
+func (p *parser) isSpace(r rune) bool {
+	switch r {
+	case ' ', '\t', '\n', '\r':
+		return true
+	}
+	return p.opts.Dialect == DialectJSON5 && (unicode.IsSpace(r) || r == '\uFEFF')
+}
+
+// readRune reads the next rune and advances the current position past it.
+// A rune that would take the document past MaxDocumentSize is left unread.
+func (p *parser) readRune() (rune, error) {
+	if p.limitErr != nil {
+		return 0, p.limitErr
+	}
+	r, size, err := p.reader.ReadRune()
+	p.last = p.pos
+	if err != nil {
+		return 0, err
+	}
+	if max := p.opts.MaxDocumentSize; max > 0 && p.pos.Offset+int64(size) > max {
+		_ = p.reader.UnreadRune()
+		p.limitErr = &LimitError{Position: p.pos, Limit: "document size", Max: max}
+		return 0, p.limitErr
+	}
+	if r == utf8.RuneError && size == 1 && p.opts.StrictUnicode {
+		_ = p.reader.UnreadRune()
+		b, _ := p.reader.ReadByte()
+		reason := fmt.Sprintf("invalid UTF-8 byte 0x%02X at byte offset %d", b, p.pos.Offset)
+		return 0, &SyntaxError{Position: p.pos, Reason: reason}
+	}
+	p.pos.Offset += int64(size)
+	if r == '\n' {
+		p.pos.Line++
+		p.pos.Column = 1
+	} else {
+		p.pos.Column++
+	}
+	return r, nil
+}
+
+// unreadRune steps back over the rune returned by the latest readRune.
+func (p *parser) unreadRune() {
+	if p.reader.UnreadRune() == nil {
+		p.pos = p.last
+	}
+}
+
+// checkLength fails with a LimitError once n exceeds a non-zero max.
+func (p *parser) checkLength(n int, max int, limit string) error {
+	if max > 0 && n > max {
+		return &LimitError{Position: p.last, Limit: limit, Max: int64(max)}
+	}
+	return nil
+}
+
+// syntaxError reports a problem with the rune returned by the latest readRune.
+func (p *parser) syntaxError(reason string, found string) error {
+	return &SyntaxError{Position: p.last, Reason: reason, Found: found}
+}
+
+// unexpected turns a failed read into an error. Running out of input while
+// one of expected is still required is a syntax error, anything else is
+// passed through as a read error.
+func (p *parser) unexpected(err error, expected ...string) error {
+	if err != io.EOF {
+		return err
+	}
+	return &SyntaxError{Position: p.last, Expected: expected, Found: endOfInput}
+}
+
+// unexpectedRune reports that r was read where one of expected was required.
+func (p *parser) unexpectedRune(r rune, expected ...string) error {
+	return &SyntaxError{Position: p.last, Expected: expected, Found: describeRune(r)}
+}
+
+func isHighSurrogate(r rune) bool {
+	return r >= 0xD800 && r < 0xDC00
+}
+
+// isDigit reports whether r is an ASCII digit; JSON numbers allow no
+// other digits.
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// isHexDigit reports whether r is a hexadecimal digit.
+func isHexDigit(r rune) bool {
+	return (r >= '0' && r <= '9') ||
+		(r >= 'a' && r <= 'f') ||
+		(r >= 'A' && r <= 'F')
+}
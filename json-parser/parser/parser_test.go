@@ -0,0 +1,486 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Value
+	}{
+		{
+			name:  "empty object",
+			input: "{}",
+			want:  &Object{},
+		},
+		{
+			name:  "empty array",
+			input: " [ ] ",
+			want:  &Array{},
+		},
+		{
+			name:  "scalars",
+			input: `[null, true, false, -0.5e+10, 12, "aé\n"]`,
+			want: &Array{Elements: []Value{
+				Null{},
+				Bool(true),
+				Bool(false),
+				Number{Literal: "-0.5e+10"},
+				Number{Literal: "12"},
+				String{Value: "aé\n", Raw: `aé\n`},
+			}},
+		},
+		{
+			name:  "nested object",
+			input: `{"a": {"b": [1]}, "c": "d"}`,
+			want: &Object{Members: []Member{
+				{Key: String{Value: "a", Raw: "a"}, Value: &Object{Members: []Member{
+					{Key: String{Value: "b", Raw: "b"}, Value: &Array{Elements: []Value{Number{Literal: "1"}}}},
+				}}},
+				{Key: String{Value: "c", Raw: "c"}, Value: String{Value: "d", Raw: "d"}},
+			}},
+		},
+		{
+			name:  "surrogate pair",
+			input: `["\ud83d\ude00"]`,
+			want:  &Array{Elements: []Value{String{Value: "😀", Raw: `\ud83d\ude00`}}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := Parse(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tc.want) {
+				t.Fatalf("unexpected output.\nexpected: %#v\nactual: %#v", tc.want, actual)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", ""},
+		{"scalar top level", `"a"`},
+		{"leading zero", "[01]"},
+		{"bad escape", `["\x"]`},
+		{"short unicode escape", `["\u12"]`},
+		{"trailing comma", "[1,]"},
+		{"trailing data", "{} x"},
+		{"unquoted key", "{a: 1}"},
+		{"plus sign", "[+1]"},
+		{"control character", "[\"a\tb\"]"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tc.input))
+			if !errors.Is(err, ErrInvalid) {
+				t.Fatalf("expected ErrInvalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestObjectGet(t *testing.T) {
+	v, err := Parse(strings.NewReader(`{"a": 1, "b": 2}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, ok := v.(*Object).Get("b")
+	if !ok || got != (Number{Literal: "2"}) {
+		t.Fatalf("unexpected value for b: %#v", got)
+	}
+	if _, ok := v.(*Object).Get("c"); ok {
+		t.Fatalf("expected no value for c")
+	}
+}
+
+func TestParseSyntaxError(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Position
+		message string
+	}{
+		{
+			name:    "missing colon",
+			input:   "{\n  \"a\" 1\n}",
+			want:    Position{Offset: 8, Line: 2, Column: 7},
+			message: `expected ':', found '1'`,
+		},
+		{
+			name:    "unterminated array",
+			input:   "[1, 2",
+			want:    Position{Offset: 5, Line: 1, Column: 6},
+			message: `expected ']' or ',', found end of input`,
+		},
+		{
+			name:    "leading zero",
+			input:   `{"a": 012}`,
+			want:    Position{Offset: 7, Line: 1, Column: 8},
+			message: `leading zero in number: found '1'`,
+		},
+		{
+			name:    "multibyte column",
+			input:   `["é", x]`,
+			want:    Position{Offset: 7, Line: 1, Column: 7},
+			message: `expected value, found 'x'`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tc.input))
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("expected *SyntaxError, got %v", err)
+			}
+			if syntaxErr.Position != tc.want {
+				t.Fatalf("unexpected position.\nexpected: %+v\nactual: %+v", tc.want, syntaxErr.Position)
+			}
+			if syntaxErr.Message() != tc.message {
+				t.Fatalf("unexpected message.\nexpected: %s\nactual: %s", tc.message, syntaxErr.Message())
+			}
+		})
+	}
+}
+
+func TestParseRFC8259(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Value
+	}{
+		{"string", ` "str" `, String{Value: "str", Raw: "str"}},
+		{"number", "42", Number{Literal: "42"}},
+		{"true", "true", Bool(true)},
+		{"null", "null\n", Null{}},
+		{"object", "{}", &Object{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseWithOptions(strings.NewReader(tc.input), Options{RFC: RFC8259})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tc.want) {
+				t.Fatalf("unexpected output.\nexpected: %#v\nactual: %#v", tc.want, actual)
+			}
+			if _, err := Parse(strings.NewReader(tc.input)); err == nil && tc.want.Kind() != ObjectKind {
+				t.Fatalf("expected RFC 4627 to reject a scalar top-level value")
+			}
+		})
+	}
+
+	for _, input := range []string{"", "42 43", `"a" x`} {
+		if _, err := ParseWithOptions(strings.NewReader(input), Options{RFC: RFC8259}); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("expected ErrInvalid for %q, got %v", input, err)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{`{"a": 1, "b": [true, null]}`, `{"b": [true, null], "a": 1.0}`, true},
+		{`[1, 2]`, `[2, 1]`, false},
+		{`["A"]`, `["A"]`, true},
+		{`[1e2]`, `[100]`, true},
+		{`{"a": 1}`, `{"a": 1, "b": 2}`, false},
+		{`[0]`, `[false]`, false},
+		{`{"a": 1, "a": 2}`, `{"a": 2}`, true},
+		{`{"a": 1, "a": 2}`, `{"a": 1}`, false},
+	}
+
+	for _, tc := range tests {
+		a, err := Parse(strings.NewReader(tc.a))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b, err := Parse(strings.NewReader(tc.b))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if actual := Equal(a, b); actual != tc.want {
+			t.Fatalf("Equal(%s, %s) = %v, expected %v", tc.a, tc.b, actual, tc.want)
+		}
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  Options
+		limit string
+		pos   Position
+	}{
+		{
+			name:  "depth",
+			input: "[[[[]]]]",
+			opts:  Options{MaxDepth: 3},
+			limit: "nesting depth",
+			pos:   Position{Offset: 3, Line: 1, Column: 4},
+		},
+		{
+			name:  "string length",
+			input: `["abc", "abcdef"]`,
+			opts:  Options{MaxStringLength: 5},
+			limit: "string length",
+		},
+		{
+			name:  "escaped string length",
+			input: `["\n\n\n"]`,
+			opts:  Options{MaxStringLength: 5},
+			limit: "string length",
+		},
+		{
+			name:  "number length",
+			input: `[1.25, -12.5e10]`,
+			opts:  Options{MaxNumberLength: 5},
+			limit: "number length",
+		},
+		{
+			name:  "document size",
+			input: `{"a": [1, 2, 3]}`,
+			opts:  Options{MaxDocumentSize: 10},
+			limit: "document size",
+			pos:   Position{Offset: 10, Line: 1, Column: 11},
+		},
+		{
+			name:  "document size at value",
+			input: `[1,2,3]`,
+			opts:  Options{MaxDocumentSize: 5},
+			limit: "document size",
+			pos:   Position{Offset: 5, Line: 1, Column: 6},
+		},
+		{
+			name:  "document size in whitespace",
+			input: "[1,   2]",
+			opts:  Options{MaxDocumentSize: 4},
+			limit: "document size",
+			pos:   Position{Offset: 4, Line: 1, Column: 5},
+		},
+		{
+			name:  "document size in surrogate pair",
+			input: `["\uD83D\uDE00"]`,
+			opts:  Options{MaxDocumentSize: 10},
+			limit: "document size",
+			pos:   Position{Offset: 10, Line: 1, Column: 11},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseWithOptions(strings.NewReader(tc.input), tc.opts)
+			var limitErr *LimitError
+			if !errors.As(err, &limitErr) || !errors.Is(err, ErrLimitExceeded) {
+				t.Fatalf("expected *LimitError, got %v", err)
+			}
+			if limitErr.Limit != tc.limit {
+				t.Fatalf("unexpected limit.\nexpected: %s\nactual: %s", tc.limit, limitErr.Limit)
+			}
+			if tc.pos != (Position{}) && limitErr.Position != tc.pos {
+				t.Fatalf("unexpected position.\nexpected: %+v\nactual: %+v", tc.pos, limitErr.Position)
+			}
+		})
+	}
+
+	within := Options{MaxDepth: 2, MaxStringLength: 3, MaxNumberLength: 4, MaxDocumentSize: 23}
+	if _, err := ParseWithOptions(strings.NewReader(`{"abc": [1234, "\n"]}  `), within); err != nil {
+		t.Fatalf("unexpected error for input within limits: %v", err)
+	}
+}
+
+func TestParseStrictUnicode(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		lax    string
+		offset int64
+	}{
+		{"lone high surrogate", `["\uD800"]`, "�", 2},
+		{"lone low surrogate", `["ab\uDC00"]`, "ab�", 4},
+		{"high surrogate without low", `["\uD800A"]`, "�A", 2},
+		{"invalid byte", "[\"a\xffb\"]", "a�b", 3},
+		{"truncated sequence", "[\"\xe2\x82\"]", "��", 2},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := Parse(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatalf("unexpected error without StrictUnicode: %v", err)
+			}
+			if s := v.(*Array).Elements[0].(String).Value; s != tc.lax {
+				t.Fatalf("unexpected value.\nexpected: %q\nactual: %q", tc.lax, s)
+			}
+
+			_, err = ParseWithOptions(strings.NewReader(tc.input), Options{StrictUnicode: true})
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("expected *SyntaxError, got %v", err)
+			}
+			if syntaxErr.Offset != tc.offset {
+				t.Fatalf("unexpected offset.\nexpected: %d\nactual: %d (%v)", tc.offset, syntaxErr.Offset, err)
+			}
+		})
+	}
+
+	v, err := ParseWithOptions(strings.NewReader(`["\uD83D\uDE00 é"]`), Options{StrictUnicode: true})
+	if err != nil {
+		t.Fatalf("unexpected error for a valid pair: %v", err)
+	}
+	if s := v.(*Array).Elements[0].(String).Value; s != "😀 é" {
+		t.Fatalf("unexpected value %q", s)
+	}
+}
+
+func TestParseStrictUnicodeBetweenTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		dialect Dialect
+		reason  string
+	}{
+		{"before value", "[1,\xff2]", DialectJSON, "invalid UTF-8 byte 0xFF at byte offset 3"},
+		{"before whitespace", "{\"a\":\xfe\xff 1}", DialectJSON, "invalid UTF-8 byte 0xFE at byte offset 5"},
+		{"in comment", "[1 /* \xff */]", DialectJSONC, "invalid UTF-8 byte 0xFF at byte offset 6"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseWithOptions(strings.NewReader(tc.input), Options{Dialect: tc.dialect, StrictUnicode: true})
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("expected *SyntaxError, got %v", err)
+			}
+			if syntaxErr.Reason != tc.reason {
+				t.Fatalf("unexpected reason.\nexpected: %s\nactual: %s", tc.reason, syntaxErr.Reason)
+			}
+		})
+	}
+}
+
+func TestNumberConversions(t *testing.T) {
+	tests := []struct {
+		literal string
+		int64   int64
+		intErr  bool
+		big     string
+		safe    bool
+	}{
+		{"42", 42, false, "42", true},
+		{"-1e3", -1000, false, "-1000", true},
+		{"2.50", 0, true, "", false},
+		{"9007199254740991", 9007199254740991, false, "9007199254740991", true},
+		{"9007199254740993", 9007199254740993, false, "9007199254740993", false},
+		{"9223372036854775808", 0, true, "9223372036854775808", false},
+		{"-123456789012345678901234567890", 0, true, "-123456789012345678901234567890", false},
+	}
+
+	for _, tc := range tests {
+		n := Number{Literal: tc.literal}
+		i, err := n.Int64()
+		if (err != nil) != tc.intErr || i != tc.int64 {
+			t.Fatalf("Int64(%s) = %d, %v", tc.literal, i, err)
+		}
+		b, err := n.BigInt()
+		if tc.big == "" {
+			if !errors.Is(err, ErrNotInteger) {
+				t.Fatalf("BigInt(%s): expected ErrNotInteger, got %v", tc.literal, err)
+			}
+		} else if err != nil || b.String() != tc.big {
+			t.Fatalf("BigInt(%s) = %v, %v", tc.literal, b, err)
+		}
+		if n.IsSafeInteger() != tc.safe {
+			t.Fatalf("IsSafeInteger(%s) = %v", tc.literal, !tc.safe)
+		}
+	}
+	if _, err := (Number{Literal: "9223372036854775808"}).Int64(); !errors.Is(err, strconv.ErrRange) {
+		t.Fatalf("expected int64 overflow to wrap strconv.ErrRange, got %v", err)
+	}
+	if i, err := (Number{Literal: "18446744073709551615"}).Uint64(); err != nil || i != 1<<64-1 {
+		t.Fatalf("Uint64(18446744073709551615) = %d, %v", i, err)
+	}
+	if _, err := (Number{Literal: "-1"}).Uint64(); !errors.Is(err, strconv.ErrRange) {
+		t.Fatalf("expected uint64 underflow to wrap strconv.ErrRange, got %v", err)
+	}
+
+	// Huge exponents are rejected without building the integer, and
+	// exponents beyond a big.Float, which parse to ±Inf or 0, as well.
+	for _, literal := range []string{"1e100000000", "-1e100000000", "1e999999999", "-1e999999999", "1e-999999999"} {
+		n := Number{Literal: literal}
+		if _, err := n.Int64(); !errors.Is(err, strconv.ErrRange) {
+			t.Fatalf("Int64(%s): expected strconv.ErrRange, got %v", literal, err)
+		}
+		if _, err := n.BigInt(); !errors.Is(err, strconv.ErrRange) {
+			t.Fatalf("BigInt(%s): expected strconv.ErrRange, got %v", literal, err)
+		}
+		if _, err := n.Uint64(); !errors.Is(err, strconv.ErrRange) {
+			t.Fatalf("Uint64(%s): expected strconv.ErrRange, got %v", literal, err)
+		}
+	}
+	for literal, want := range map[string]bool{"0e-999999999": true, "-0.0": true, "1e999999999": true, "1e-999999999": false, "0.5": false} {
+		if actual := (Number{Literal: literal}).IsInteger(); actual != want {
+			t.Fatalf("IsInteger(%s) = %v", literal, actual)
+		}
+	}
+}
+
+func TestParseNumberModes(t *testing.T) {
+	tests := []struct {
+		input string
+		mode  NumberMode
+		valid bool
+	}{
+		{"[1.5, 1e308]", NumberFloat64, true},
+		{"[1e309]", NumberFloat64, false},
+		{"[-9223372036854775808, 1.0]", NumberInt64, true},
+		{"[9223372036854775808]", NumberInt64, false},
+		{"[0.5]", NumberInt64, false},
+		{"[1e400, 0.1]", NumberExact, true},
+		{"[1e-999999999]", NumberInt64, false},
+		{"[0e-999999999]", NumberInt64, true},
+		{"[1e999999999]", NumberInt64, false},
+	}
+
+	for _, tc := range tests {
+		_, err := ParseWithOptions(strings.NewReader(tc.input), Options{Numbers: tc.mode})
+		if (err == nil) != tc.valid {
+			t.Fatalf("unexpected result for %s in %s mode: %v", tc.input, tc.mode, err)
+		}
+	}
+
+	var warnings []string
+	opts := Options{WarnUnsafeIntegers: true, Warn: func(err error) {
+		warnings = append(warnings, err.Error())
+	}}
+	if _, err := ParseWithOptions(strings.NewReader("[9007199254740991, -9007199254740993, 1e300, 0.5, 1e999999999, 1e-999999999]"), opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"line 1, column 20: integer -9007199254740993 is beyond 2^53 and loses precision as a double",
+		"line 1, column 39: integer 1e300 is beyond 2^53 and loses precision as a double",
+		"line 1, column 51: integer 1e999999999 is beyond 2^53 and loses precision as a double",
+	}
+	if !slices.Equal(warnings, want) {
+		t.Fatalf("unexpected warnings.\nexpected: %q\nactual: %q", want, warnings)
+	}
+}
@@ -0,0 +1,364 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// UnmarshalError reports a value that could not be stored in the Go value
+// passed to Unmarshal.
+type UnmarshalError struct {
+	// Path is the JSON Pointer of the offending value, e.g. "/items/0/id".
+	Path string
+	// Reason describes the problem, e.g. "cannot unmarshal string into int".
+	Reason string
+}
+
+func (e *UnmarshalError) Error() string {
+	return fmt.Sprintf("%s at %q", e.Reason, e.Path)
+}
+
+// Unmarshal parses data and stores the result in the value pointed to by v,
+// which must be a non-nil pointer. Any value is accepted at the top level.
+//
+// Objects fill structs, using the name from a field's `json` tag or else
+// its name, matched exactly or failing that case-insensitively, and maps
+// with string keys. Arrays fill slices and arrays. Numbers fill integers
+// and floats, failing on overflow, as well as Number, big.Int and
+// big.Float. A field of type Value receives the subtree as parsed. An
+// interface{} receives map[string]any, []any, string, float64, bool or nil.
+// null sets pointers, maps, slices and interfaces to nil and leaves other
+// values alone.
+func Unmarshal(data []byte, v any) error {
+	return UnmarshalWithOptions(data, v, Options{RFC: RFC8259})
+}
+
+// UnmarshalWithOptions is like Unmarshal but parses data according to
+// opts. With opts.DisallowUnknownFields, object members without a matching
+// struct field are an error.
+func UnmarshalWithOptions(data []byte, v any, opts Options) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("Unmarshal needs a non-nil pointer, got %T", v)
+	}
+	tree, err := ParseWithOptions(bytes.NewReader(data), opts)
+	if err != nil {
+		return err
+	}
+	d := &unmarshaler{strict: opts.DisallowUnknownFields}
+	d.value(tree, rv.Elem())
+	return d.err
+}
+
+// unmarshaler stores a value tree in Go values. Like encoding/json it
+// keeps going after a mismatch and returns the first one.
+type unmarshaler struct {
+	strict bool
+	path   []string
+	err    error
+}
+
+var (
+	valueType    = reflect.TypeFor[Value]()
+	numberType   = reflect.TypeFor[Number]()
+	bigIntType   = reflect.TypeFor[big.Int]()
+	bigFloatType = reflect.TypeFor[big.Float]()
+)
+
+func (d *unmarshaler) fail(format string, args ...any) {
+	if d.err != nil {
+		return
+	}
+	var sb strings.Builder
+	for _, t := range d.path {
+		sb.WriteByte('/')
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(t, "~", "~0"), "/", "~1"))
+	}
+	d.err = &UnmarshalError{Path: sb.String(), Reason: fmt.Sprintf(format, args...)}
+}
+
+func (d *unmarshaler) mismatch(v Value, t reflect.Type) {
+	d.fail("cannot unmarshal %s into %s", v.Kind(), t)
+}
+
+func (d *unmarshaler) value(v Value, rv reflect.Value) {
+	if rv.Type() == valueType {
+		rv.Set(reflect.ValueOf(&v).Elem())
+		return
+	}
+	if _, ok := v.(Null); ok {
+		switch rv.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+			rv.SetZero()
+		}
+		return
+	}
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		d.value(v, rv.Elem())
+		return
+	}
+	if rv.Kind() == reflect.Interface {
+		if rv.NumMethod() > 0 {
+			d.mismatch(v, rv.Type())
+			return
+		}
+		rv.Set(reflect.ValueOf(d.plain(v)))
+		return
+	}
+
+	switch v := v.(type) {
+	case *Object:
+		d.object(v, rv)
+	case *Array:
+		d.array(v, rv)
+	case String:
+		if rv.Kind() != reflect.String {
+			d.mismatch(v, rv.Type())
+			return
+		}
+		rv.SetString(v.Value)
+	case Number:
+		d.number(v, rv)
+	case Bool:
+		if rv.Kind() != reflect.Bool {
+			d.mismatch(v, rv.Type())
+			return
+		}
+		rv.SetBool(bool(v))
+	}
+}
+
+func (d *unmarshaler) number(n Number, rv reflect.Value) {
+	switch rv.Type() {
+	case numberType:
+		rv.Set(reflect.ValueOf(n))
+		return
+	case bigIntType:
+		i, err := n.BigInt()
+		if err != nil {
+			d.fail("cannot unmarshal %s into %s", n.Literal, rv.Type())
+			return
+		}
+		rv.Set(reflect.ValueOf(i).Elem())
+		return
+	case bigFloatType:
+		f, err := n.BigFloat()
+		if err != nil {
+			d.fail("cannot unmarshal %s into %s", n.Literal, rv.Type())
+			return
+		}
+		rv.Set(reflect.ValueOf(f).Elem())
+		return
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := n.Int64()
+		if err != nil || rv.OverflowInt(i) {
+			d.fail("number %s overflows or is not an integer for %s", n.Literal, rv.Type())
+			return
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i, err := n.Uint64()
+		if err != nil || rv.OverflowUint(i) {
+			d.fail("number %s overflows or is not an integer for %s", n.Literal, rv.Type())
+			return
+		}
+		rv.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := n.Float64()
+		if err != nil || rv.OverflowFloat(f) {
+			d.fail("number %s overflows %s", n.Literal, rv.Type())
+			return
+		}
+		rv.SetFloat(f)
+	default:
+		d.mismatch(n, rv.Type())
+	}
+}
+
+func (d *unmarshaler) array(a *Array, rv reflect.Value) {
+	switch rv.Kind() {
+	case reflect.Slice:
+		rv.Set(reflect.MakeSlice(rv.Type(), len(a.Elements), len(a.Elements)))
+	case reflect.Array:
+		// Extra elements are dropped and missing ones zeroed.
+		rv.SetZero()
+	default:
+		d.mismatch(a, rv.Type())
+		return
+	}
+	for i, e := range a.Elements {
+		if i >= rv.Len() {
+			break
+		}
+		d.path = append(d.path, fmt.Sprint(i))
+		d.value(e, rv.Index(i))
+		d.path = d.path[:len(d.path)-1]
+	}
+}
+
+func (d *unmarshaler) object(o *Object, rv reflect.Value) {
+	switch {
+	case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(rv.Type()))
+		}
+		for _, m := range o.Members {
+			d.path = append(d.path, m.Key.Value)
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			d.value(m.Value, elem)
+			rv.SetMapIndex(reflect.ValueOf(m.Key.Value).Convert(rv.Type().Key()), elem)
+			d.path = d.path[:len(d.path)-1]
+		}
+	case rv.Kind() == reflect.Struct:
+		fields := cachedFields(rv.Type())
+		for _, m := range o.Members {
+			d.path = append(d.path, m.Key.Value)
+			if f, ok := fields.lookup(m.Key.Value); ok {
+				d.value(m.Value, fieldByIndex(rv, f.index))
+			} else if d.strict {
+				d.fail("unknown field %q in %s", m.Key.Value, rv.Type())
+			}
+			d.path = d.path[:len(d.path)-1]
+		}
+	default:
+		d.mismatch(o, rv.Type())
+	}
+}
+
+// plain converts v to the Go values used for interface{} targets.
+func (d *unmarshaler) plain(v Value) any {
+	switch v := v.(type) {
+	case *Object:
+		m := make(map[string]any, len(v.Members))
+		for _, member := range v.Members {
+			d.path = append(d.path, member.Key.Value)
+			m[member.Key.Value] = d.plain(member.Value)
+			d.path = d.path[:len(d.path)-1]
+		}
+		return m
+	case *Array:
+		s := make([]any, len(v.Elements))
+		for i, e := range v.Elements {
+			d.path = append(d.path, fmt.Sprint(i))
+			s[i] = d.plain(e)
+			d.path = d.path[:len(d.path)-1]
+		}
+		return s
+	case String:
+		return v.Value
+	case Number:
+		f, err := v.Float64()
+		if err != nil {
+			d.fail("number %s overflows %s", v.Literal, reflect.TypeFor[float64]())
+		}
+		return f
+	case Bool:
+		return bool(v)
+	}
+	return nil
+}
+
+// field is a struct field that can be set from an object member. index
+// leads through embedded structs as for reflect.Value.FieldByIndex.
+type field struct {
+	name  string
+	index []int
+}
+
+type structFields []field
+
+// lookup finds the field for a member name, preferring an exact match.
+func (fs structFields) lookup(name string) (field, bool) {
+	for _, f := range fs {
+		if f.name == name {
+			return f, true
+		}
+	}
+	for _, f := range fs {
+		if strings.EqualFold(f.name, name) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+var fieldCache sync.Map // reflect.Type -> structFields
+
+func cachedFields(t reflect.Type) structFields {
+	if fs, ok := fieldCache.Load(t); ok {
+		return fs.(structFields)
+	}
+	fs, _ := fieldCache.LoadOrStore(t, typeFields(t, nil, map[reflect.Type]bool{}))
+	return fs.(structFields)
+}
+
+// typeFields lists the settable fields of t, including those promoted from
+// embedded structs without a tag. Fields of the outer struct win over
+// promoted fields with the same name. visiting holds the structs being
+// listed further up, whose fields an embedding like struct{ *T } would
+// only repeat, so they are not entered again.
+func typeFields(t reflect.Type, index []int, visiting map[reflect.Type]bool) structFields {
+	visiting[t] = true
+	defer delete(visiting, t)
+	var fields, promoted structFields
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		idx := append(index[:len(index):len(index)], i)
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			// A nil pointer to an unexported type could not be allocated,
+			// and a struct being listed already would recurse forever.
+			if visiting[ft] || (sf.Type.Kind() == reflect.Pointer && !sf.IsExported()) {
+				continue
+			}
+			promoted = append(promoted, typeFields(ft, idx, visiting)...)
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, field{name: name, index: idx})
+	}
+	for _, p := range promoted {
+		if !slices.ContainsFunc(fields, func(f field) bool { return f.name == p.name }) {
+			fields = append(fields, p)
+		}
+	}
+	return fields
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but allocates nil
+// embedded struct pointers on the way.
+func fieldByIndex(rv reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv
+}
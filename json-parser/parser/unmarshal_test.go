@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+type unmarshalBase struct {
+	ID      int64 `json:"id"`
+	Created string
+}
+
+type unmarshalItem struct {
+	unmarshalBase
+	Name    string            `json:"name"`
+	Tags    []string          `json:"tags,omitempty"`
+	Counts  map[string]uint8  `json:"counts"`
+	Pos     [2]float64        `json:"pos"`
+	Parent  *unmarshalItem    `json:"parent"`
+	Extra   any               `json:"extra"`
+	Raw     Value             `json:"raw"`
+	Big     *big.Int          `json:"big"`
+	Exact   Number            `json:"exact"`
+	Ignored string            `json:"-"`
+	Labels  map[string]string `json:"labels"`
+	hidden  int
+}
+
+// UnmarshalNode embeds itself, which is legal through a pointer. It is
+// exported because embedded pointers to unexported types are skipped.
+type UnmarshalNode struct {
+	*UnmarshalNode
+	Value int
+}
+
+func TestUnmarshal(t *testing.T) {
+	data := `{
+		"id": 7, "created": "today", "NAME": "x", "tags": ["a", "b"],
+		"counts": {"a": 1, "b": 255}, "pos": [1.5, -2, 9],
+		"parent": {"id": 1, "name": "root", "parent": null},
+		"extra": {"list": [1, "two", true, null]},
+		"raw": [1, {"b": 2}], "big": 123456789012345678901234567890, "exact": 1.50,
+		"Ignored": "no", "labels": null, "hidden": 3, "unknown": 1
+	}`
+	var actual unmarshalItem
+	actual.Labels = map[string]string{"old": "value"}
+	if err := Unmarshal([]byte(data), &actual); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantBig, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	want := unmarshalItem{
+		unmarshalBase: unmarshalBase{ID: 7, Created: "today"},
+		Name:          "x",
+		Tags:          []string{"a", "b"},
+		Counts:        map[string]uint8{"a": 1, "b": 255},
+		Pos:           [2]float64{1.5, -2},
+		Parent:        &unmarshalItem{unmarshalBase: unmarshalBase{ID: 1}, Name: "root"},
+		Extra:         map[string]any{"list": []any{1.0, "two", true, nil}},
+		Raw: &Array{Elements: []Value{
+			Number{Literal: "1"},
+			&Object{Members: []Member{{Key: String{Value: "b", Raw: "b"}, Value: Number{Literal: "2"}}}},
+		}},
+		Big:   wantBig,
+		Exact: Number{Literal: "1.50"},
+	}
+	if !reflect.DeepEqual(actual, want) {
+		t.Fatalf("unexpected result.\nexpected: %+v\nactual: %+v", want, actual)
+	}
+
+	var s []int
+	if err := Unmarshal([]byte(" [1, 2] "), &s); err != nil || !reflect.DeepEqual(s, []int{1, 2}) {
+		t.Fatalf("unexpected result %v, %v", s, err)
+	}
+	var str string
+	if err := Unmarshal([]byte(`"top"`), &str); err != nil || str != "top" {
+		t.Fatalf("unexpected result %q, %v", str, err)
+	}
+	var node UnmarshalNode
+	if err := Unmarshal([]byte(`{"Value": 3}`), &node); err != nil || node.Value != 3 || node.UnmarshalNode != nil {
+		t.Fatalf("unexpected result %+v, %v", node, err)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		target any
+		strict bool
+		path   string
+		reason string
+	}{
+		{"type mismatch", `{"name": 1}`, &unmarshalItem{}, false, "/name", "cannot unmarshal number into string"},
+		{"nested path", `{"parent": {"tags": ["a", {}]}}`, &unmarshalItem{}, false, "/parent/tags/1", "cannot unmarshal object into string"},
+		{"overflow", `{"counts": {"a/b": 256}}`, &unmarshalItem{}, false, "/counts/a~1b", "number 256 overflows or is not an integer for uint8"},
+		{"fraction", `[1.5]`, &[]int{}, false, "/0", "number 1.5 overflows or is not an integer for int"},
+		{"unknown field", `{"id": 1, "nope": true}`, &unmarshalItem{}, true, "/nope", `unknown field "nope" in parser.unmarshalItem`},
+		{"first error wins", `{"id": "a", "name": 2}`, &unmarshalItem{}, false, "/id", "cannot unmarshal string into int64"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := UnmarshalWithOptions([]byte(tc.data), tc.target, Options{RFC: RFC8259, DisallowUnknownFields: tc.strict})
+			var unmarshalErr *UnmarshalError
+			if !errors.As(err, &unmarshalErr) {
+				t.Fatalf("expected *UnmarshalError, got %v", err)
+			}
+			if unmarshalErr.Path != tc.path || unmarshalErr.Reason != tc.reason {
+				t.Fatalf("unexpected error.\nexpected: %s at %q\nactual: %v", tc.reason, tc.path, err)
+			}
+		})
+	}
+
+	var v map[string]any
+	if err := Unmarshal([]byte(`{"a": }`), &v); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid, got %v", err)
+	}
+	if err := Unmarshal([]byte(`{}`), v); err == nil {
+		t.Fatalf("expected an error for a non-pointer target")
+	}
+}
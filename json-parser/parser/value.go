@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Kind identifies the JSON type of a Value.
+type Kind int
+
+const (
+	NullKind Kind = iota
+	BoolKind
+	NumberKind
+	StringKind
+	ArrayKind
+	ObjectKind
+)
+
+func (k Kind) String() string {
+	switch k {
+	case NullKind:
+		return "null"
+	case BoolKind:
+		return "boolean"
+	case NumberKind:
+		return "number"
+	case StringKind:
+		return "string"
+	case ArrayKind:
+		return "array"
+	case ObjectKind:
+		return "object"
+	}
+	return "unknown"
+}
+
+// Value is a node of a parsed JSON document. The concrete type is one of
+// *Object, *Array, String, Number, Bool or Null.
+type Value interface {
+	Kind() Kind
+}
+
+// Member is a single key/value pair of an object.
+type Member struct {
+	Key   String
+	Value Value
+}
+
+// Object keeps its members in the order they appeared in the input.
+type Object struct {
+	Members []Member
+}
+
+func (o *Object) Kind() Kind { return ObjectKind }
+
+// Get returns the value of the last member named key.
+func (o *Object) Get(key string) (Value, bool) {
+	for i := len(o.Members) - 1; i >= 0; i-- {
+		if o.Members[i].Key.Value == key {
+			return o.Members[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+type Array struct {
+	Elements []Value
+}
+
+func (a *Array) Kind() Kind { return ArrayKind }
+
+// String holds the decoded text in Value and the text exactly as it was
+// written between the quotes in Raw.
+type String struct {
+	Value string
+	Raw   string
+}
+
+func (s String) Kind() Kind { return StringKind }
+
+// Number keeps the literal text of the number so no precision is lost.
+type Number struct {
+	Literal string
+}
+
+func (n Number) Kind() Kind { return NumberKind }
+
+type Bool bool
+
+func (b Bool) Kind() Kind { return BoolKind }
+
+type Null struct{}
+
+func (Null) Kind() Kind { return NullKind }
+
+// Float64 returns the number as the nearest float64.
+func (n Number) Float64() (float64, error) {
+	return strconv.ParseFloat(n.Literal, 64)
+}
+
+// Equal reports whether a and b hold the same JSON value. Objects are
+// compared regardless of member order and numbers by their numeric value,
+// so 1 and 1.0 are equal.
+func Equal(a, b Value) bool {
+	switch a := a.(type) {
+	case *Object:
+		b, ok := b.(*Object)
+		if !ok {
+			return false
+		}
+		// Duplicate keys are compared by their last value, as Get sees them.
+		for _, m := range a.Members {
+			av, _ := a.Get(m.Key.Value)
+			bv, ok := b.Get(m.Key.Value)
+			if !ok || !Equal(av, bv) {
+				return false
+			}
+		}
+		for _, m := range b.Members {
+			if _, ok := a.Get(m.Key.Value); !ok {
+				return false
+			}
+		}
+		return true
+	case *Array:
+		b, ok := b.(*Array)
+		if !ok || len(a.Elements) != len(b.Elements) {
+			return false
+		}
+		for i := range a.Elements {
+			if !Equal(a.Elements[i], b.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case String:
+		b, ok := b.(String)
+		return ok && a.Value == b.Value
+	case Number:
+		b, ok := b.(Number)
+		if !ok {
+			return false
+		}
+		return a.Literal == b.Literal || a.Cmp(b) == 0
+	case Bool:
+		b, ok := b.(Bool)
+		return ok && a == b
+	case Null:
+		_, ok := b.(Null)
+		return ok
+	}
+	return false
+}
+
+// numberPrec is the precision used to compare numbers, enough to tell
+// apart any two literals of realistic length.
+const numberPrec = 1024
+
+// Cmp compares n and m by numeric value and returns -1, 0 or +1.
+func (n Number) Cmp(m Number) int {
+	x, _, errX := new(big.Float).SetPrec(numberPrec).Parse(n.Literal, 10)
+	y, _, errY := new(big.Float).SetPrec(numberPrec).Parse(m.Literal, 10)
+	if errX != nil || errY != nil {
+		return strings.Compare(n.Literal, m.Literal)
+	}
+	return x.Cmp(y)
+}
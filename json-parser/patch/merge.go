@@ -0,0 +1,26 @@
+package patch
+
+import "json-parser/parser"
+
+// Merge applies an RFC 7386 JSON Merge Patch to doc. Members of an object
+// patch replace those of doc, null members delete them and any other patch
+// value replaces doc entirely. doc is left untouched.
+func Merge(doc, patch parser.Value) parser.Value {
+	p, ok := patch.(*parser.Object)
+	if !ok {
+		return Clone(patch)
+	}
+	target, ok := Clone(doc).(*parser.Object)
+	if !ok {
+		target = &parser.Object{}
+	}
+	for _, m := range p.Members {
+		if _, isNull := m.Value.(parser.Null); isNull {
+			target.Members = deleteMembers(target.Members, m.Key.Value)
+			continue
+		}
+		current, _ := target.Get(m.Key.Value)
+		set(target, m.Key.Value, Merge(current, m.Value))
+	}
+	return target
+}
@@ -0,0 +1,297 @@
+// Package patch applies RFC 6902 JSON Patch and RFC 7386 JSON Merge Patch
+// documents to parsed values.
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"json-parser/parser"
+	"json-parser/pointer"
+	"slices"
+)
+
+// OperationError reports which operation of a JSON Patch failed.
+type OperationError struct {
+	// Index is the position of the operation in the patch document.
+	Index int
+	Op    string
+	Err   error
+}
+
+func (e *OperationError) Error() string {
+	if e.Op == "" {
+		return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+	}
+	return fmt.Sprintf("operation %d (%s): %v", e.Index, e.Op, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// ErrTestFailed is wrapped by every *TestError.
+var ErrTestFailed = errors.New("test failed")
+
+// TestError reports a "test" operation whose value did not match.
+type TestError struct {
+	Path pointer.Pointer
+	// Expected is the value from the patch, Actual the one in the document.
+	Expected parser.Value
+	Actual   parser.Value
+}
+
+func (e *TestError) Error() string {
+	return fmt.Sprintf("test failed at %q: expected %s, found %s",
+		e.Path.String(), parser.Marshal(e.Expected), parser.Marshal(e.Actual))
+}
+
+func (e *TestError) Unwrap() error {
+	return ErrTestFailed
+}
+
+// operation is one decoded member of a JSON Patch document.
+type operation struct {
+	op       string
+	path     pointer.Pointer
+	from     pointer.Pointer
+	value    parser.Value
+	hasValue bool
+}
+
+// Apply applies a JSON Patch to doc and returns the result. The patch is
+// applied to a copy, so doc is left untouched, also when an operation
+// fails.
+func Apply(doc parser.Value, patch parser.Value) (parser.Value, error) {
+	ops, err := decode(patch)
+	if err != nil {
+		return nil, err
+	}
+	doc = Clone(doc)
+	for i, op := range ops {
+		if doc, err = op.apply(doc); err != nil {
+			return nil, &OperationError{Index: i, Op: op.op, Err: err}
+		}
+	}
+	return doc, nil
+}
+
+func decode(patch parser.Value) ([]operation, error) {
+	arr, ok := patch.(*parser.Array)
+	if !ok {
+		return nil, fmt.Errorf("a JSON Patch must be an array, found %s", patch.Kind())
+	}
+	ops := make([]operation, 0, len(arr.Elements))
+	for i, e := range arr.Elements {
+		op, err := decodeOperation(e)
+		if err != nil {
+			return nil, &OperationError{Index: i, Op: op.op, Err: err}
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+func decodeOperation(v parser.Value) (operation, error) {
+	var op operation
+	obj, ok := v.(*parser.Object)
+	if !ok {
+		return op, fmt.Errorf("expected an object, found %s", v.Kind())
+	}
+	name, err := stringMember(obj, "op")
+	if err != nil {
+		return op, err
+	}
+	op.op = name
+	switch op.op {
+	case "add", "remove", "replace", "move", "copy", "test":
+	default:
+		return op, fmt.Errorf("unknown op %q", op.op)
+	}
+	if op.path, err = pointerMember(obj, "path"); err != nil {
+		return op, err
+	}
+	if op.op == "move" || op.op == "copy" {
+		if op.from, err = pointerMember(obj, "from"); err != nil {
+			return op, err
+		}
+	}
+	op.value, op.hasValue = obj.Get("value")
+	if !op.hasValue && (op.op == "add" || op.op == "replace" || op.op == "test") {
+		return op, errors.New(`missing member "value"`)
+	}
+	return op, nil
+}
+
+func stringMember(obj *parser.Object, name string) (string, error) {
+	v, ok := obj.Get(name)
+	if !ok {
+		return "", fmt.Errorf("missing member %q", name)
+	}
+	s, ok := v.(parser.String)
+	if !ok {
+		return "", fmt.Errorf("member %q must be a string, found %s", name, v.Kind())
+	}
+	return s.Value, nil
+}
+
+func pointerMember(obj *parser.Object, name string) (pointer.Pointer, error) {
+	s, err := stringMember(obj, name)
+	if err != nil {
+		return nil, err
+	}
+	return pointer.Parse(s)
+}
+
+func (op operation) apply(doc parser.Value) (parser.Value, error) {
+	switch op.op {
+	case "add":
+		return add(doc, op.path, op.value)
+	case "remove":
+		_, doc, err := remove(doc, op.path)
+		return doc, err
+	case "replace":
+		return replace(doc, op.path, op.value)
+	case "move":
+		if op.from.String() == op.path.String() {
+			return doc, nil
+		}
+		if isProperPrefix(op.from, op.path) {
+			return nil, fmt.Errorf("cannot move %q into its own child %q", op.from.String(), op.path.String())
+		}
+		v, doc, err := remove(doc, op.from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.path, v)
+	case "copy":
+		v, err := op.from.Resolve(doc)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.path, Clone(v))
+	case "test":
+		v, err := op.path.Resolve(doc)
+		if err != nil {
+			return nil, err
+		}
+		if !parser.Equal(v, op.value) {
+			return nil, &TestError{Path: op.path, Expected: op.value, Actual: v}
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown op %q", op.op)
+}
+
+// add inserts v at path and returns the new document, which is v itself
+// when path is the root.
+func add(doc parser.Value, path pointer.Pointer, v parser.Value) (parser.Value, error) {
+	if len(path) == 0 {
+		return v, nil
+	}
+	parent, err := path[:len(path)-1].Resolve(doc)
+	if err != nil {
+		return nil, err
+	}
+	token := path[len(path)-1]
+	switch c := parent.(type) {
+	case *parser.Object:
+		set(c, token, v)
+	case *parser.Array:
+		if token == "-" {
+			c.Elements = append(c.Elements, v)
+			break
+		}
+		// Inserting right after the last element is allowed.
+		i, err := pointer.ArrayIndex(token, len(c.Elements)+1)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		c.Elements = slices.Insert(c.Elements, i, v)
+	default:
+		return nil, fmt.Errorf("%s: cannot add to %s", path, parent.Kind())
+	}
+	return doc, nil
+}
+
+// remove deletes the value at path and returns it together with the new
+// document.
+func remove(doc parser.Value, path pointer.Pointer) (parser.Value, parser.Value, error) {
+	v, err := path.Resolve(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(path) == 0 {
+		return nil, nil, errors.New("cannot remove the whole document")
+	}
+	parent, _ := path[:len(path)-1].Resolve(doc)
+	token := path[len(path)-1]
+	switch c := parent.(type) {
+	case *parser.Object:
+		c.Members = deleteMembers(c.Members, token)
+	case *parser.Array:
+		i, _ := pointer.ArrayIndex(token, len(c.Elements))
+		c.Elements = slices.Delete(c.Elements, i, i+1)
+	}
+	return v, doc, nil
+}
+
+// replace swaps the value at path for v and returns the new document. The
+// member or element keeps its position.
+func replace(doc parser.Value, path pointer.Pointer, v parser.Value) (parser.Value, error) {
+	if len(path) == 0 {
+		return v, nil
+	}
+	if _, err := path.Resolve(doc); err != nil {
+		return nil, err
+	}
+	parent, _ := path[:len(path)-1].Resolve(doc)
+	token := path[len(path)-1]
+	switch c := parent.(type) {
+	case *parser.Object:
+		set(c, token, v)
+	case *parser.Array:
+		i, _ := pointer.ArrayIndex(token, len(c.Elements))
+		c.Elements[i] = v
+	}
+	return doc, nil
+}
+
+// set replaces the members named key with a single one holding v, or
+// appends a member if there is none.
+func set(obj *parser.Object, key string, v parser.Value) {
+	i := slices.IndexFunc(obj.Members, func(m parser.Member) bool { return m.Key.Value == key })
+	if i < 0 {
+		obj.Members = append(obj.Members, parser.Member{Key: parser.NewString(key), Value: v})
+		return
+	}
+	obj.Members[i].Value = v
+	rest := deleteMembers(obj.Members[i+1:], key)
+	obj.Members = obj.Members[:i+1+len(rest)]
+}
+
+func deleteMembers(members []parser.Member, key string) []parser.Member {
+	return slices.DeleteFunc(members, func(m parser.Member) bool { return m.Key.Value == key })
+}
+
+func isProperPrefix(prefix, path pointer.Pointer) bool {
+	return len(prefix) < len(path) && slices.Equal(prefix, path[:len(prefix)])
+}
+
+// Clone returns a deep copy of v.
+func Clone(v parser.Value) parser.Value {
+	switch v := v.(type) {
+	case *parser.Object:
+		c := &parser.Object{Members: make([]parser.Member, len(v.Members))}
+		for i, m := range v.Members {
+			c.Members[i] = parser.Member{Key: m.Key, Value: Clone(m.Value)}
+		}
+		return c
+	case *parser.Array:
+		c := &parser.Array{Elements: make([]parser.Value, len(v.Elements))}
+		for i, e := range v.Elements {
+			c.Elements[i] = Clone(e)
+		}
+		return c
+	}
+	return v
+}
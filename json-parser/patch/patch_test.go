@@ -0,0 +1,175 @@
+package patch
+
+import (
+	"errors"
+	"json-parser/parser"
+	"strings"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"add member", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"foo":"bar","baz":"qux"}`},
+		{"add element", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"append element", `[1]`, `[{"op":"add","path":"/-","value":2},{"op":"add","path":"/2","value":3}]`, `[1,2,3]`},
+		{"replace existing member on add", `{"a":1}`, `[{"op":"add","path":"/a","value":[2]}]`, `{"a":[2]}`},
+		{"remove", `{"baz":"qux","foo":["bar","x"]}`, `[{"op":"remove","path":"/baz"},{"op":"remove","path":"/foo/0"}]`, `{"foo":["x"]}`},
+		{"replace", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"replace element", `[1,2,3]`, `[{"op":"replace","path":"/1","value":0}]`, `[1,0,3]`},
+		{"replace root", `{"a":1}`, `[{"op":"replace","path":"","value":[]}]`, `[]`},
+		{"move", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"move element", `[1,2,3,4]`, `[{"op":"move","from":"/1","path":"/3"}]`, `[1,3,4,2]`},
+		{"copy", `{"a":{"b":1}}`, `[{"op":"copy","from":"/a","path":"/c"},{"op":"add","path":"/c/d","value":2}]`, `{"a":{"b":1},"c":{"b":1,"d":2}}`},
+		{"test", `{"a":[1,{"b":2.0}]}`, `[{"op":"test","path":"/a","value":[1,{"b":2}]}]`, `{"a":[1,{"b":2.0}]}`},
+		{"escaped path", `{"a/b":1,"m~n":2}`, `[{"op":"remove","path":"/a~1b"},{"op":"replace","path":"/m~0n","value":3}]`, `{"m~n":3}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := parser.Parse(strings.NewReader(tc.doc))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			patch, err := parser.Parse(strings.NewReader(tc.patch))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual, err := Apply(doc, patch)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s := string(parser.Marshal(actual)); s != tc.want {
+				t.Fatalf("unexpected result.\nexpected: %s\nactual: %s", tc.want, s)
+			}
+			if s := string(parser.Marshal(doc)); s != tc.doc {
+				t.Fatalf("the input document was modified: %s", s)
+			}
+		})
+	}
+}
+
+func TestApplyKeepsMemberOrder(t *testing.T) {
+	doc, err := parser.Parse(strings.NewReader(`{"b":[1,2],"a":1,"c":{"d":1,"e":2}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	patch, err := parser.Parse(strings.NewReader(`[{"op":"replace","path":"/b","value":0},{"op":"replace","path":"/c/d","value":3},{"op":"add","path":"/a","value":2}]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actual, err := Apply(doc, patch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"b":0,"a":2,"c":{"d":3,"e":2}}`
+	if s := string(parser.Marshal(actual)); s != want {
+		t.Fatalf("unexpected result.\nexpected: %s\nactual: %s", want, s)
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		index int
+		msg   string
+	}{
+		{"not an array", `{}`, `{}`, -1, "a JSON Patch must be an array, found object"},
+		{"unknown op", `{}`, `[{"op":"frob","path":""}]`, 0, `operation 0 (frob): unknown op "frob"`},
+		{"missing value", `{}`, `[{"op":"add","path":"/a"}]`, 0, `operation 0 (add): missing member "value"`},
+		{"missing parent", `{}`, `[{"op":"add","path":"/a/b","value":1}]`, 0, `operation 0 (add): /a: member "a" not found`},
+		{"index out of range", `[1]`, `[{"op":"add","path":"/2","value":1}]`, 0, "operation 0 (add): /2: array index 2 out of range"},
+		{"remove missing", `{"a":1}`, `[{"op":"test","path":"/a","value":1},{"op":"remove","path":"/b"}]`, 1, `operation 1 (remove): /b: member "b" not found`},
+		{"replace missing", `[1]`, `[{"op":"replace","path":"/1","value":2}]`, 0, "operation 0 (replace): /1: array index 1 out of range"},
+		{"move into child", `{"a":{}}`, `[{"op":"move","from":"/a","path":"/a/b"}]`, 0, `operation 0 (move): cannot move "/a" into its own child "/a/b"`},
+		{"failed test", `{"a":{"b":"x"}}`, `[{"op":"test","path":"/a/b","value":"y"}]`, 0, `operation 0 (test): test failed at "/a/b": expected "y", found "x"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := parser.Parse(strings.NewReader(tc.doc))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			patch, err := parser.Parse(strings.NewReader(tc.patch))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_, err = Apply(doc, patch)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if err.Error() != tc.msg {
+				t.Fatalf("unexpected error.\nexpected: %s\nactual: %s", tc.msg, err)
+			}
+			var opErr *OperationError
+			if errors.As(err, &opErr) != (tc.index >= 0) || (opErr != nil && opErr.Index != tc.index) {
+				t.Fatalf("unexpected operation index in %v, expected %d", err, tc.index)
+			}
+		})
+	}
+
+	doc, err := parser.Parse(strings.NewReader(`[1]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	patch, err := parser.Parse(strings.NewReader(`[{"op":"test","path":"/0","value":2}]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = Apply(doc, patch)
+	var testErr *TestError
+	if !errors.As(err, &testErr) || !errors.Is(err, ErrTestFailed) {
+		t.Fatalf("expected *TestError, got %v", err)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	// Examples from RFC 7386, Appendix A.
+	tests := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	opts := parser.Options{RFC: parser.RFC8259}
+	for _, tc := range tests {
+		doc, err := parser.ParseWithOptions(strings.NewReader(tc.doc), opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		patch, err := parser.ParseWithOptions(strings.NewReader(tc.patch), opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		actual := Merge(doc, patch)
+		if s := string(parser.Marshal(actual)); s != tc.want {
+			t.Fatalf("unexpected result of merging %s into %s.\nexpected: %s\nactual: %s", tc.patch, tc.doc, tc.want, s)
+		}
+		if s := string(parser.Marshal(doc)); s != tc.doc {
+			t.Fatalf("the input document %s was modified", tc.doc)
+		}
+	}
+}
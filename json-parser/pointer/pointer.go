@@ -0,0 +1,93 @@
+// Package pointer implements RFC 6901 JSON Pointers over parsed documents.
+package pointer
+
+import (
+	"fmt"
+	"json-parser/parser"
+	"strconv"
+	"strings"
+)
+
+// Pointer is a sequence of reference tokens, already unescaped. The empty
+// Pointer refers to the whole document.
+type Pointer []string
+
+// Parse parses the string form of a pointer, such as "/a/0/b~1c".
+func Parse(s string) (Pointer, error) {
+	if s == "" {
+		return Pointer{}, nil
+	}
+	if s[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must be empty or start with '/'", s)
+	}
+	tokens := strings.Split(s[1:], "/")
+	for i, t := range tokens {
+		for j := 0; j < len(t); j++ {
+			if t[j] == '~' && (j+1 == len(t) || (t[j+1] != '0' && t[j+1] != '1')) {
+				return nil, fmt.Errorf("invalid JSON pointer %q: bad escape in %q", s, t)
+			}
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return Pointer(tokens), nil
+}
+
+// String returns the pointer with '~' and '/' in tokens escaped.
+func (p Pointer) String() string {
+	var sb strings.Builder
+	for _, t := range p {
+		sb.WriteByte('/')
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(t, "~", "~0"), "/", "~1"))
+	}
+	return sb.String()
+}
+
+// Append returns a new pointer with token added at the end. p is never
+// modified, so pointers can be extended from a shared prefix.
+func (p Pointer) Append(token string) Pointer {
+	q := make(Pointer, len(p), len(p)+1)
+	copy(q, p)
+	return append(q, token)
+}
+
+// AppendIndex is like Append for an array index.
+func (p Pointer) AppendIndex(i int) Pointer {
+	return p.Append(strconv.Itoa(i))
+}
+
+// Resolve returns the value p refers to inside doc.
+func (p Pointer) Resolve(doc parser.Value) (parser.Value, error) {
+	v := doc
+	for i, t := range p {
+		switch c := v.(type) {
+		case *parser.Object:
+			next, ok := c.Get(t)
+			if !ok {
+				return nil, fmt.Errorf("%s: member %q not found", p[:i+1], t)
+			}
+			v = next
+		case *parser.Array:
+			idx, err := ArrayIndex(t, len(c.Elements))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", p[:i+1], err)
+			}
+			v = c.Elements[idx]
+		default:
+			return nil, fmt.Errorf("%s: cannot index into %s", p[:i+1], v.Kind())
+		}
+	}
+	return v, nil
+}
+
+// ArrayIndex converts a reference token to an index into an array of the
+// given length. Leading zeros and signs are rejected as RFC 6901 requires.
+func ArrayIndex(token string, length int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx >= length {
+		return 0, fmt.Errorf("array index %s out of range", token)
+	}
+	return idx, nil
+}
@@ -0,0 +1,81 @@
+package pointer
+
+import (
+	"json-parser/parser"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Pointer
+	}{
+		{"", Pointer{}},
+		{"/", Pointer{""}},
+		{"/a/0", Pointer{"a", "0"}},
+		{"/a~1b/m~0n", Pointer{"a/b", "m~n"}},
+		{"/~01", Pointer{"~1"}},
+	}
+
+	for _, tc := range tests {
+		actual, err := Parse(tc.input)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", tc.input, err)
+		}
+		if !slices.Equal(actual, tc.want) {
+			t.Fatalf("unexpected output for %q.\nexpected: %q\nactual: %q", tc.input, tc.want, actual)
+		}
+		if actual.String() != tc.input {
+			t.Fatalf("pointer %q does not round-trip, got %q", tc.input, actual.String())
+		}
+	}
+
+	for _, input := range []string{"a", "/~2", "/a~"} {
+		if _, err := Parse(input); err == nil {
+			t.Fatalf("expected an error for %q", input)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	doc, err := parser.Parse(strings.NewReader(`{"a": [10, {"b/c": true}], "": 1}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		pointer string
+		want    string
+		wantErr bool
+	}{
+		{"/a/0", "10", false},
+		{"/a/1/b~1c", "true", false},
+		{"/", "1", false},
+		{"/a/2", "", true},
+		{"/a/01", "", true},
+		{"/a/-", "", true},
+		{"/x", "", true},
+		{"/a/0/x", "", true},
+	}
+
+	for _, tc := range tests {
+		p, err := Parse(tc.pointer)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, err := p.Resolve(doc)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("expected an error for %q", tc.pointer)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", tc.pointer, err)
+		}
+		if actual := string(parser.Marshal(v)); actual != tc.want {
+			t.Fatalf("unexpected value for %q.\nexpected: %s\nactual: %s", tc.pointer, tc.want, actual)
+		}
+	}
+}
@@ -0,0 +1,352 @@
+// Package schema validates parsed documents against a JSON Schema (draft
+// 2020-12). The supported keywords are type, enum, const, required,
+// properties, additionalProperties, items, prefixItems, minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, minLength, maxLength, minItems,
+// maxItems, pattern, allOf, anyOf, oneOf, not and local $ref.
+package schema
+
+import (
+	"fmt"
+	"json-parser/parser"
+	"json-parser/pointer"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	root *node
+}
+
+// node holds the keywords of a single (sub)schema. A nil keyword field
+// means the keyword is absent.
+type node struct {
+	// always is set for the boolean schemas true and false.
+	always *bool
+
+	ref *node
+
+	types    []string
+	enum     []parser.Value
+	constVal parser.Value
+
+	required             []string
+	properties           []property
+	additionalProperties *node
+
+	prefixItems []*node
+	items       *node
+
+	minimum, maximum                   *parser.Number
+	exclusiveMinimum, exclusiveMaximum *parser.Number
+
+	minLength, maxLength *int
+	minItems, maxItems   *int
+	pattern              *regexp.Regexp
+
+	allOf, anyOf, oneOf []*node
+	not                 *node
+}
+
+type property struct {
+	name   string
+	schema *node
+}
+
+// Violation is one way in which a document does not match the schema.
+type Violation struct {
+	// Path points at the offending value in the document.
+	Path pointer.Pointer
+	// Keyword is the schema keyword that failed, e.g. "required".
+	Keyword string
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Path, v.Message)
+}
+
+type compiler struct {
+	doc parser.Value
+	// compiled memoizes subschemas by their JSON Pointer into doc, which
+	// also lets recursive $refs point back at a node being compiled.
+	compiled map[string]*node
+}
+
+// Compile checks that doc is a well-formed schema and prepares it for
+// validation.
+func Compile(doc parser.Value) (*Schema, error) {
+	c := &compiler{doc: doc, compiled: make(map[string]*node)}
+	root, err := c.compileAt(pointer.Pointer{})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkCycles(); err != nil {
+		return nil, err
+	}
+	return &Schema{root: root}, nil
+}
+
+// checkCycles rejects $refs that lead back to a schema applied to the same
+// value without descending into it first, as in {"$ref": "#"}. Validation
+// would recurse forever on them.
+func (c *compiler) checkCycles() error {
+	const (
+		visiting = iota + 1
+		done
+	)
+	keys := make(map[*node]string, len(c.compiled))
+	for key, n := range c.compiled {
+		keys[n] = key
+	}
+	state := make(map[*node]int, len(c.compiled))
+	var visit func(n *node) error
+	visit = func(n *node) error {
+		switch state[n] {
+		case visiting:
+			return fmt.Errorf("schema: $ref cycle through %q never descends into the document", "#"+keys[n])
+		case done:
+			return nil
+		}
+		state[n] = visiting
+		for _, next := range n.inPlace() {
+			if err := visit(next); err != nil {
+				return err
+			}
+		}
+		state[n] = done
+		return nil
+	}
+	for _, key := range slices.Sorted(maps.Keys(c.compiled)) {
+		if err := visit(c.compiled[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inPlace returns the subschemas applied to the same value as n.
+func (n *node) inPlace() []*node {
+	var list []*node
+	if n.ref != nil {
+		list = append(list, n.ref)
+	}
+	if n.not != nil {
+		list = append(list, n.not)
+	}
+	list = append(list, n.allOf...)
+	list = append(list, n.anyOf...)
+	return append(list, n.oneOf...)
+}
+
+func (c *compiler) compileAt(ptr pointer.Pointer) (*node, error) {
+	key := ptr.String()
+	if n, ok := c.compiled[key]; ok {
+		return n, nil
+	}
+	v, err := ptr.Resolve(c.doc)
+	if err != nil {
+		return nil, err
+	}
+	n := &node{}
+	c.compiled[key] = n
+	if err := c.fill(n, v, ptr); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func (c *compiler) fill(n *node, v parser.Value, ptr pointer.Pointer) error {
+	if b, ok := v.(parser.Bool); ok {
+		always := bool(b)
+		n.always = &always
+		return nil
+	}
+	obj, ok := v.(*parser.Object)
+	if !ok {
+		return fmt.Errorf("schema %s: expected an object or a boolean, found %s", ptr, v.Kind())
+	}
+	for _, m := range obj.Members {
+		kw := m.Key.Value
+		at := ptr.Append(kw)
+		var err error
+		switch kw {
+		case "$ref":
+			n.ref, err = c.ref(m.Value, at)
+		case "type":
+			n.types, err = typeNames(m.Value, at)
+		case "enum":
+			arr, ok := m.Value.(*parser.Array)
+			if !ok {
+				return fmt.Errorf("schema %s: expected an array", at)
+			}
+			n.enum = arr.Elements
+		case "const":
+			n.constVal = m.Value
+		case "required":
+			n.required, err = stringList(m.Value, at)
+		case "properties":
+			props, ok := m.Value.(*parser.Object)
+			if !ok {
+				return fmt.Errorf("schema %s: expected an object", at)
+			}
+			for _, p := range props.Members {
+				sub, err := c.compileAt(at.Append(p.Key.Value))
+				if err != nil {
+					return err
+				}
+				n.properties = append(n.properties, property{name: p.Key.Value, schema: sub})
+			}
+		case "additionalProperties":
+			n.additionalProperties, err = c.compileAt(at)
+		case "items":
+			n.items, err = c.compileAt(at)
+		case "prefixItems", "allOf", "anyOf", "oneOf":
+			var list []*node
+			if list, err = c.schemaList(m.Value, at); err != nil {
+				return err
+			}
+			switch kw {
+			case "prefixItems":
+				n.prefixItems = list
+			case "allOf":
+				n.allOf = list
+			case "anyOf":
+				n.anyOf = list
+			case "oneOf":
+				n.oneOf = list
+			}
+		case "not":
+			n.not, err = c.compileAt(at)
+		case "minimum":
+			n.minimum, err = number(m.Value, at)
+		case "maximum":
+			n.maximum, err = number(m.Value, at)
+		case "exclusiveMinimum":
+			n.exclusiveMinimum, err = number(m.Value, at)
+		case "exclusiveMaximum":
+			n.exclusiveMaximum, err = number(m.Value, at)
+		case "minLength":
+			n.minLength, err = count(m.Value, at)
+		case "maxLength":
+			n.maxLength, err = count(m.Value, at)
+		case "minItems":
+			n.minItems, err = count(m.Value, at)
+		case "maxItems":
+			n.maxItems, err = count(m.Value, at)
+		case "pattern":
+			s, ok := m.Value.(parser.String)
+			if !ok {
+				return fmt.Errorf("schema %s: expected a string", at)
+			}
+			if n.pattern, err = regexp.Compile(s.Value); err != nil {
+				return fmt.Errorf("schema %s: %w", at, err)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ref compiles the target of a $ref. Only references into the same
+// document ("#", "#/$defs/name", ...) are supported.
+func (c *compiler) ref(v parser.Value, at pointer.Pointer) (*node, error) {
+	s, ok := v.(parser.String)
+	if !ok {
+		return nil, fmt.Errorf("schema %s: expected a string", at)
+	}
+	if !strings.HasPrefix(s.Value, "#") {
+		return nil, fmt.Errorf("schema %s: only local references starting with '#' are supported, got %q", at, s.Value)
+	}
+	fragment, err := url.PathUnescape(s.Value[1:])
+	if err != nil {
+		return nil, fmt.Errorf("schema %s: %w", at, err)
+	}
+	target, err := pointer.Parse(fragment)
+	if err != nil {
+		return nil, fmt.Errorf("schema %s: %w", at, err)
+	}
+	n, err := c.compileAt(target)
+	if err != nil {
+		return nil, fmt.Errorf("schema %s: cannot resolve %q: %w", at, s.Value, err)
+	}
+	return n, nil
+}
+
+func (c *compiler) schemaList(v parser.Value, at pointer.Pointer) ([]*node, error) {
+	arr, ok := v.(*parser.Array)
+	if !ok {
+		return nil, fmt.Errorf("schema %s: expected an array", at)
+	}
+	list := make([]*node, 0, len(arr.Elements))
+	for i := range arr.Elements {
+		n, err := c.compileAt(at.AppendIndex(i))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, n)
+	}
+	return list, nil
+}
+
+var knownTypes = []string{"null", "boolean", "object", "array", "number", "string", "integer"}
+
+func typeNames(v parser.Value, at pointer.Pointer) ([]string, error) {
+	var names []string
+	if s, ok := v.(parser.String); ok {
+		names = []string{s.Value}
+	} else {
+		var err error
+		if names, err = stringList(v, at); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range names {
+		if !slices.Contains(knownTypes, name) {
+			return nil, fmt.Errorf("schema %s: unknown type %q", at, name)
+		}
+	}
+	return names, nil
+}
+
+func stringList(v parser.Value, at pointer.Pointer) ([]string, error) {
+	arr, ok := v.(*parser.Array)
+	if !ok {
+		return nil, fmt.Errorf("schema %s: expected an array of strings", at)
+	}
+	list := make([]string, 0, len(arr.Elements))
+	for _, e := range arr.Elements {
+		s, ok := e.(parser.String)
+		if !ok {
+			return nil, fmt.Errorf("schema %s: expected an array of strings", at)
+		}
+		list = append(list, s.Value)
+	}
+	return list, nil
+}
+
+func number(v parser.Value, at pointer.Pointer) (*parser.Number, error) {
+	n, ok := v.(parser.Number)
+	if !ok {
+		return nil, fmt.Errorf("schema %s: expected a number", at)
+	}
+	return &n, nil
+}
+
+func count(v parser.Value, at pointer.Pointer) (*int, error) {
+	n, ok := v.(parser.Number)
+	if !ok {
+		return nil, fmt.Errorf("schema %s: expected a non-negative integer", at)
+	}
+	f, err := n.Float64()
+	if err != nil || f < 0 || f != float64(int(f)) {
+		return nil, fmt.Errorf("schema %s: expected a non-negative integer", at)
+	}
+	i := int(f)
+	return &i, nil
+}
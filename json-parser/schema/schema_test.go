@@ -0,0 +1,128 @@
+package schema
+
+import (
+	"json-parser/parser"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["name", "port"],
+	"properties": {
+		"name": {"type": "string", "minLength": 1, "pattern": "^[a-z-]+$"},
+		"port": {"type": "integer", "minimum": 1, "exclusiveMaximum": 65536},
+		"mode": {"enum": ["dev", "prod"]},
+		"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2},
+		"upstream": {"$ref": "#/$defs/upstream"}
+	},
+	"additionalProperties": false,
+	"$defs": {
+		"upstream": {
+			"type": "object",
+			"properties": {
+				"next": {"anyOf": [{"type": "null"}, {"$ref": "#/$defs/upstream"}]},
+				"weight": {"type": ["number", "null"], "maximum": 1}
+			}
+		}
+	}
+}`
+
+func TestValidate(t *testing.T) {
+	schemaDoc, err := parser.Parse(strings.NewReader(testSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s, err := Compile(schemaDoc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name string
+		doc  string
+		want []string
+	}{
+		{
+			name: "valid",
+			doc:  `{"name": "api", "port": 8080, "mode": "dev", "tags": ["a"], "upstream": {"next": {"next": null, "weight": 0.5}}}`,
+			want: nil,
+		},
+		{
+			name: "missing and wrong types",
+			doc:  `{"name": 1, "tags": ["a", 2, "c"]}`,
+			want: []string{
+				` required`,
+				`/name type`,
+				`/tags/1 type`,
+				`/tags maxItems`,
+			},
+		},
+		{
+			name: "ranges, enum and pattern",
+			doc:  `{"name": "API", "port": 65536.0, "mode": "test", "extra": true}`,
+			want: []string{
+				`/name pattern`,
+				`/port exclusiveMaximum`,
+				`/mode enum`,
+				`/extra false`,
+			},
+		},
+		{
+			name: "underflowing exponent is not an integer",
+			doc:  `{"name": "a", "port": 1e-999999999}`,
+			want: []string{
+				`/port type`,
+				`/port minimum`,
+			},
+		},
+		{
+			name: "recursive ref",
+			doc:  `{"name": "a", "port": 1.5, "upstream": {"next": {"next": 3}}}`,
+			want: []string{
+				`/port type`,
+				`/upstream/next anyOf`,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := parser.Parse(strings.NewReader(tc.doc))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var actual []string
+			for _, v := range s.Validate(doc) {
+				actual = append(actual, v.Path.String()+" "+v.Keyword)
+			}
+			if !slices.Equal(actual, tc.want) {
+				t.Fatalf("unexpected violations.\nexpected: %q\nactual: %q", tc.want, actual)
+			}
+		})
+	}
+}
+
+func TestCompileInvalid(t *testing.T) {
+	tests := []string{
+		`{"type": "text"}`,
+		`{"minimum": "1"}`,
+		`{"pattern": "("}`,
+		`{"$ref": "other.json#/a"}`,
+		`{"$ref": "#/$defs/missing"}`,
+		`{"properties": {"a": 1}}`,
+		`{"$ref": "#"}`,
+		`{"$ref": "#/$defs/a", "$defs": {"a": {"$ref": "#/$defs/a"}}}`,
+		`{"anyOf": [{"$ref": "#/$defs/a"}], "$defs": {"a": {"allOf": [{"$ref": "#/$defs/b"}]}, "b": {"not": {"$ref": "#/$defs/a"}}}}`,
+	}
+
+	for _, input := range tests {
+		doc, err := parser.Parse(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := Compile(doc); err == nil {
+			t.Fatalf("expected an error compiling %s", input)
+		}
+	}
+}
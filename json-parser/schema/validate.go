@@ -0,0 +1,143 @@
+package schema
+
+import (
+	"fmt"
+	"json-parser/parser"
+	"json-parser/pointer"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validate checks doc against the schema and returns every violation found,
+// or nil if the document is valid.
+func (s *Schema) Validate(doc parser.Value) []Violation {
+	return s.root.validate(doc, pointer.Pointer{}, nil)
+}
+
+func (n *node) validate(v parser.Value, path pointer.Pointer, out []Violation) []Violation {
+	report := func(keyword, format string, args ...any) {
+		out = append(out, Violation{Path: path, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if n.always != nil {
+		if !*n.always {
+			report("false", "no value is allowed here")
+		}
+		return out
+	}
+	if n.ref != nil {
+		out = n.ref.validate(v, path, out)
+	}
+
+	if n.types != nil && !slices.ContainsFunc(n.types, func(t string) bool { return hasType(v, t) }) {
+		report("type", "expected %s, found %s", strings.Join(n.types, " or "), typeName(v))
+	}
+	if n.enum != nil && !slices.ContainsFunc(n.enum, func(e parser.Value) bool { return parser.Equal(v, e) }) {
+		report("enum", "value is not one of the allowed values")
+	}
+	if n.constVal != nil && !parser.Equal(v, n.constVal) {
+		report("const", "value must be %s", parser.Marshal(n.constVal))
+	}
+
+	switch v := v.(type) {
+	case *parser.Object:
+		for _, name := range n.required {
+			if _, ok := v.Get(name); !ok {
+				report("required", "missing required property %q", name)
+			}
+		}
+		for _, m := range v.Members {
+			matched := false
+			for _, p := range n.properties {
+				if p.name == m.Key.Value {
+					out = p.schema.validate(m.Value, path.Append(m.Key.Value), out)
+					matched = true
+				}
+			}
+			if !matched && n.additionalProperties != nil {
+				out = n.additionalProperties.validate(m.Value, path.Append(m.Key.Value), out)
+			}
+		}
+	case *parser.Array:
+		for i, e := range v.Elements {
+			if i < len(n.prefixItems) {
+				out = n.prefixItems[i].validate(e, path.AppendIndex(i), out)
+			} else if n.items != nil {
+				out = n.items.validate(e, path.AppendIndex(i), out)
+			}
+		}
+		if n.minItems != nil && len(v.Elements) < *n.minItems {
+			report("minItems", "expected at least %d items, found %d", *n.minItems, len(v.Elements))
+		}
+		if n.maxItems != nil && len(v.Elements) > *n.maxItems {
+			report("maxItems", "expected at most %d items, found %d", *n.maxItems, len(v.Elements))
+		}
+	case parser.String:
+		length := utf8.RuneCountInString(v.Value)
+		if n.minLength != nil && length < *n.minLength {
+			report("minLength", "expected at least %d characters, found %d", *n.minLength, length)
+		}
+		if n.maxLength != nil && length > *n.maxLength {
+			report("maxLength", "expected at most %d characters, found %d", *n.maxLength, length)
+		}
+		if n.pattern != nil && !n.pattern.MatchString(v.Value) {
+			report("pattern", "%q does not match pattern %q", v.Value, n.pattern)
+		}
+	case parser.Number:
+		if n.minimum != nil && v.Cmp(*n.minimum) < 0 {
+			report("minimum", "%s is less than the minimum of %s", v.Literal, n.minimum.Literal)
+		}
+		if n.maximum != nil && v.Cmp(*n.maximum) > 0 {
+			report("maximum", "%s is greater than the maximum of %s", v.Literal, n.maximum.Literal)
+		}
+		if n.exclusiveMinimum != nil && v.Cmp(*n.exclusiveMinimum) <= 0 {
+			report("exclusiveMinimum", "%s must be greater than %s", v.Literal, n.exclusiveMinimum.Literal)
+		}
+		if n.exclusiveMaximum != nil && v.Cmp(*n.exclusiveMaximum) >= 0 {
+			report("exclusiveMaximum", "%s must be less than %s", v.Literal, n.exclusiveMaximum.Literal)
+		}
+	}
+
+	for _, sub := range n.allOf {
+		out = sub.validate(v, path, out)
+	}
+	if n.anyOf != nil && !slices.ContainsFunc(n.anyOf, func(sub *node) bool { return sub.matches(v, path) }) {
+		report("anyOf", "value does not match any of the anyOf schemas")
+	}
+	if n.oneOf != nil {
+		matched := 0
+		for _, sub := range n.oneOf {
+			if sub.matches(v, path) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			report("oneOf", "value matches %d of the oneOf schemas, expected exactly 1", matched)
+		}
+	}
+	if n.not != nil && n.not.matches(v, path) {
+		report("not", "value must not match the schema in not")
+	}
+	return out
+}
+
+func (n *node) matches(v parser.Value, path pointer.Pointer) bool {
+	return len(n.validate(v, path, nil)) == 0
+}
+
+func hasType(v parser.Value, t string) bool {
+	switch t {
+	case "integer":
+		num, ok := v.(parser.Number)
+		return ok && num.IsInteger()
+	case "number":
+		return v.Kind() == parser.NumberKind
+	}
+	return typeName(v) == t
+}
+
+// typeName returns the JSON Schema name of the type of v.
+func typeName(v parser.Value) string {
+	return v.Kind().String()
+}
@@ -0,0 +1,163 @@
+// Package stats profiles a document: how deeply it nests, how many values
+// of each kind it holds and which parts of it take up the most room.
+package stats
+
+import (
+	"cmp"
+	"io"
+	"json-parser/parser"
+	"json-parser/pointer"
+	"slices"
+)
+
+// Entry is a value in the document and its size. What the size counts
+// depends on the list the entry is in.
+type Entry struct {
+	Path pointer.Pointer
+	Size int64
+}
+
+// KeyCount is how often a key appears across all objects in a document.
+type KeyCount struct {
+	Key   string
+	Count int
+}
+
+// Stats is the profile of a single document.
+type Stats struct {
+	// Size is the offset just past the end of the root value.
+	Size     int64
+	MaxDepth int
+
+	Objects int
+	Arrays  int
+	Strings int
+	Numbers int
+	Bools   int
+	Nulls   int
+
+	// LargestArrays are the arrays with the most elements and
+	// LongestStrings the strings with the most bytes once decoded, both
+	// largest first.
+	LargestArrays  []Entry
+	LongestStrings []Entry
+	// Keys counts every object key, most frequent first.
+	Keys []KeyCount
+	// Members gives the size in bytes of each member of a top-level
+	// object, from the start of its key to the end of its value, in
+	// document order.
+	Members []Entry
+}
+
+// frame is an open object or array.
+type frame struct {
+	kind   parser.Kind
+	path   pointer.Pointer
+	count  int
+	key    string
+	keyPos parser.Position
+}
+
+// Collect reads one document from dec and profiles it, keeping the top
+// largest arrays and longest strings.
+func Collect(dec *parser.Decoder, top int) (*Stats, error) {
+	s := &Stats{}
+	keys := make(map[string]int)
+	var stack []*frame
+	// endMember records the size of a top-level member that ends at end.
+	endMember := func(end parser.Position) {
+		if len(stack) == 1 && stack[0].kind == parser.ObjectKind {
+			f := stack[0]
+			s.Members = append(s.Members, Entry{Path: pointer.Pointer{f.key}, Size: end.Offset - f.keyPos.Offset})
+		}
+	}
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.Size = tok.End.Offset
+
+		switch tok.Kind {
+		case parser.ObjectKey:
+			f := stack[len(stack)-1]
+			f.key = tok.Value.(parser.String).Value
+			f.keyPos = tok.Pos
+			keys[f.key]++
+			continue
+		case parser.ObjectEnd, parser.ArrayEnd:
+			f := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if f.kind == parser.ArrayKind {
+				s.LargestArrays = keep(s.LargestArrays, Entry{Path: f.path, Size: int64(f.count)}, top)
+			}
+			endMember(tok.End)
+			continue
+		}
+
+		var path pointer.Pointer
+		if len(stack) > 0 {
+			parent := stack[len(stack)-1]
+			if parent.kind == parser.ObjectKind {
+				path = parent.path.Append(parent.key)
+			} else {
+				path = parent.path.AppendIndex(parent.count)
+			}
+			parent.count++
+		}
+		switch tok.Kind {
+		case parser.ObjectStart:
+			s.Objects++
+			stack = append(stack, &frame{kind: parser.ObjectKind, path: path})
+			s.MaxDepth = max(s.MaxDepth, len(stack))
+			continue
+		case parser.ArrayStart:
+			s.Arrays++
+			stack = append(stack, &frame{kind: parser.ArrayKind, path: path})
+			s.MaxDepth = max(s.MaxDepth, len(stack))
+			continue
+		}
+		switch v := tok.Value.(type) {
+		case parser.String:
+			s.Strings++
+			s.LongestStrings = keep(s.LongestStrings, Entry{Path: path, Size: int64(len(v.Value))}, top)
+		case parser.Number:
+			s.Numbers++
+		case parser.Bool:
+			s.Bools++
+		case parser.Null:
+			s.Nulls++
+		}
+		endMember(tok.End)
+	}
+
+	for k, n := range keys {
+		s.Keys = append(s.Keys, KeyCount{Key: k, Count: n})
+	}
+	slices.SortFunc(s.Keys, func(a, b KeyCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return s, nil
+}
+
+// keep inserts e into list, which is sorted largest first, if it is among
+// the n largest. Of entries of equal size the earliest stays first.
+func keep(list []Entry, e Entry, n int) []Entry {
+	i, _ := slices.BinarySearchFunc(list, e, func(x, e Entry) int {
+		if x.Size >= e.Size {
+			return -1
+		}
+		return 1
+	})
+	if i >= n {
+		return list
+	}
+	list = slices.Insert(list, i, e)
+	return list[:min(len(list), n)]
+}
@@ -0,0 +1,71 @@
+package stats
+
+import (
+	"json-parser/parser"
+	"json-parser/pointer"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCollect(t *testing.T) {
+	input := `{"id": 1, "tags": ["a", "bb", "ccc"], "items": [{"id": 2, "name": "widget", "ok": true}, {"id": 3, "note": null}, []]}`
+	s, err := Collect(parser.NewDecoder(strings.NewReader(input)), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	counts := []int{s.MaxDepth, s.Objects, s.Arrays, s.Strings, s.Numbers, s.Bools, s.Nulls}
+	if want := []int{3, 3, 3, 4, 3, 1, 1}; !reflect.DeepEqual(counts, want) {
+		t.Fatalf("unexpected depth and counts.\nexpected: %v\nactual: %v", want, counts)
+	}
+	if s.Size != int64(len(input)) {
+		t.Fatalf("unexpected size.\nexpected: %d\nactual: %d", len(input), s.Size)
+	}
+
+	tests := []struct {
+		name   string
+		actual []Entry
+		want   []Entry
+	}{
+		{"LargestArrays", s.LargestArrays, []Entry{
+			{pointer.Pointer{"tags"}, 3},
+			{pointer.Pointer{"items"}, 3},
+		}},
+		{"LongestStrings", s.LongestStrings, []Entry{
+			{pointer.Pointer{"items", "0", "name"}, 6},
+			{pointer.Pointer{"tags", "2"}, 3},
+		}},
+		{"Members", s.Members, []Entry{
+			{pointer.Pointer{"id"}, 7},
+			{pointer.Pointer{"tags"}, 26},
+			{pointer.Pointer{"items"}, 79},
+		}},
+	}
+	for _, tc := range tests {
+		if !reflect.DeepEqual(tc.actual, tc.want) {
+			t.Fatalf("unexpected %s.\nexpected: %v\nactual: %v", tc.name, tc.want, tc.actual)
+		}
+	}
+
+	wantKeys := []KeyCount{{"id", 3}, {"items", 1}, {"name", 1}, {"note", 1}, {"ok", 1}, {"tags", 1}}
+	if !reflect.DeepEqual(s.Keys, wantKeys) {
+		t.Fatalf("unexpected keys.\nexpected: %v\nactual: %v", wantKeys, s.Keys)
+	}
+}
+
+func TestCollectScalar(t *testing.T) {
+	actual, err := Collect(parser.NewDecoderWithOptions(strings.NewReader(` "x" `), parser.Options{RFC: parser.RFC8259}), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &Stats{Size: 4, Strings: 1, LongestStrings: []Entry{{nil, 1}}}
+	if !reflect.DeepEqual(actual, want) {
+		t.Fatalf("unexpected output.\nexpected: %+v\nactual: %+v", want, actual)
+	}
+}
+
+func TestCollectInvalid(t *testing.T) {
+	if _, err := Collect(parser.NewDecoder(strings.NewReader(`{"a": [1,}`)), 10); err == nil {
+		t.Fatalf("expected a syntax error")
+	}
+}